@@ -1,19 +1,116 @@
 package main
 
 import (
+	"context"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
+	"github.com/GradiyantoS/go-dealls-test-app/config"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/GradiyantoS/go-dealls-test-app/scheduler"
+	"github.com/GradiyantoS/go-dealls-test-app/verification"
 	"github.com/joho/godotenv"
 )
 
+const (
+	// premiumExpiryInterval is how often lapsed premium subscriptions are cleaned up
+	premiumExpiryInterval = time.Minute
+	// quotaResetInterval is how often the quota reset job checks whether a new day started
+	quotaResetInterval = time.Minute
+)
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: .env file not found. Falling back to environment variables.")
 	}
-	router := routes.SetupRouter()
+
+	userRepo, err := newUserRepository()
+	if err != nil {
+		log.Fatal("Failed to initialise user repository: ", err)
+	}
+	if closer, ok := userRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// PLANS_CONFIG_PATH points to a custom plan catalogue, the bundled one is used otherwise
+	plans, err := config.LoadPlans(os.Getenv("PLANS_CONFIG_PATH"))
+	if err != nil {
+		log.Fatal("Failed to load plan catalogue: ", err)
+	}
+
+	// RANKING_WEIGHTS overrides the weights of the candidate ranking, e.g. "recency=0.5,interests=0.5"
+	rankingWeights, err := config.LoadRankingWeights(os.Getenv("RANKING_WEIGHTS"))
+	if err != nil {
+		log.Fatal("Failed to load ranking weights: ", err)
+	}
+
+	deps := routes.NewDependencies(userRepo, plans, newPaymentProvider(), newVerificationChecker(), rankingWeights)
+	// ADMIN_API_KEY is sent as X-Admin-Key to the /admin endpoints, they are disabled without it
+	deps.AdminAPIKey = os.Getenv("ADMIN_API_KEY")
+	if deps.AdminAPIKey == "" {
+		log.Println("Warning: ADMIN_API_KEY is not set, admin endpoints are disabled")
+	}
+
+	jobs := scheduler.NewScheduler()
+	jobs.Every(premiumExpiryInterval, scheduler.NewPremiumExpiryJob(deps.UserService))
+	jobs.Every(quotaResetInterval, scheduler.NewQuotaResetJob(deps.SwipeService))
+	jobs.Start(context.Background())
+	defer jobs.Stop()
+
+	router := routes.SetupRouterWithDependencies(deps)
 	// Start the server
 	log.Println("Server running on port 8080")
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
+
+// newPaymentProvider picks the payment gateway from the PAYMENT_PROVIDER environment variable,
+// only the local fake provider is available for now
+func newPaymentProvider() payments.PaymentProvider {
+	secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET is required to verify payment webhooks")
+	}
+
+	switch provider := os.Getenv("PAYMENT_PROVIDER"); provider {
+	case "", "fake":
+		log.Println("Using fake payment provider")
+		return payments.NewFakeProvider(secret)
+	default:
+		log.Fatal("Unknown payment provider: ", provider)
+		return nil
+	}
+}
+
+// newVerificationChecker picks the automated verification checker from the VERIFICATION_CHECKER
+// environment variable, only manual reviews by administrators are available for now
+func newVerificationChecker() verification.Checker {
+	switch checker := os.Getenv("VERIFICATION_CHECKER"); checker {
+	case "", "manual":
+		log.Println("Using manual verification reviews")
+		return verification.NewManualReview()
+	default:
+		log.Fatal("Unknown verification checker: ", checker)
+		return nil
+	}
+}
+
+// newUserRepository picks the storage backend from the DB_DRIVER environment variable
+func newUserRepository() (repositories.UserRepository, error) {
+	switch os.Getenv("DB_DRIVER") {
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "dealls.db"
+		}
+		log.Println("Using SQLite repository:", path)
+		return repositories.NewSQLiteUserRepository(path)
+	default:
+		log.Println("Using in-memory repository")
+		return repositories.NewUserRepository(), nil
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/GradiyantoS/go-dealls-test-app/migrations"
+	"github.com/joho/godotenv"
+	_ "modernc.org/sqlite"
+)
+
+const usage = "usage: migrate up|down|status"
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found. Falling back to environment variables.")
+	}
+
+	if len(os.Args) != 2 {
+		log.Fatal(usage)
+	}
+
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = "dealls.db"
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		log.Fatal("Failed to open database: ", err)
+	}
+	defer db.Close()
+
+	migrator := migrations.NewMigrator(db)
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			log.Printf("Applied %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			log.Println("Database is already up to date")
+		}
+	case "down":
+		rolledBack, err := migrator.Down()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if rolledBack == nil {
+			log.Println("No migration to roll back")
+			return
+		}
+		log.Printf("Rolled back %d_%s", rolledBack.Version, rolledBack.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-30s %s\n", status.Version, status.Name, state)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
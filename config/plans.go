@@ -0,0 +1,73 @@
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+//go:embed plans.json
+var defaultPlans []byte
+
+// PremiumFeatures lists the feature names a plan may include
+var PremiumFeatures = map[string]bool{
+	"UnlimitedSwipes": true,
+	"ProfileBoost":    true,
+	"Rewind":          true,
+	"IsVerified":      true, // old name of ProfileBoost, kept so existing catalogues still load
+}
+
+// LoadPlans reads the plan catalogue from the JSON file at path, the bundled catalogue is used when path is empty
+func LoadPlans(path string) ([]models.Plan, error) {
+	data := defaultPlans
+	if path != "" {
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data = fileData
+	}
+
+	var plans []models.Plan
+	if err := json.Unmarshal(data, &plans); err != nil {
+		return nil, errors.New("invalid plan catalogue: " + err.Error())
+	}
+	if err := validatePlans(plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func validatePlans(plans []models.Plan) error {
+	if len(plans) == 0 {
+		return errors.New("invalid plan catalogue: no plans defined")
+	}
+
+	seen := map[string]bool{}
+	for _, plan := range plans {
+		switch {
+		case plan.ID == "":
+			return errors.New("invalid plan catalogue: plan without id")
+		case seen[plan.ID]:
+			return errors.New("invalid plan catalogue: duplicate plan id " + plan.ID)
+		case plan.DurationDays <= 0:
+			return errors.New("invalid plan catalogue: plan " + plan.ID + " must have a positive duration")
+		case plan.Price < 0:
+			return errors.New("invalid plan catalogue: plan " + plan.ID + " has a negative price")
+		case plan.Currency == "":
+			return errors.New("invalid plan catalogue: plan " + plan.ID + " has no currency")
+		case len(plan.Features) == 0:
+			return errors.New("invalid plan catalogue: plan " + plan.ID + " has no features")
+		}
+		for _, feature := range plan.Features {
+			if !PremiumFeatures[feature] {
+				return errors.New("invalid plan catalogue: plan " + plan.ID + " has unknown feature " + feature)
+			}
+		}
+		seen[plan.ID] = true
+	}
+	return nil
+}
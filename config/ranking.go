@@ -0,0 +1,39 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+)
+
+// LoadRankingWeights parses weights like "recency=0.4,interests=0.6". Scorers that aren't
+// listed keep their default weight, an empty value returns the defaults.
+func LoadRankingWeights(value string) (services.RankingWeights, error) {
+	weights := services.DefaultRankingWeights
+	if strings.TrimSpace(value) == "" {
+		return weights, nil
+	}
+
+	targets := map[string]*float64{
+		"recency":      &weights.Recency,
+		"completeness": &weights.Completeness,
+		"interests":    &weights.InterestOverlap,
+		"desirability": &weights.Desirability,
+		"proximity":    &weights.Proximity,
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, rawWeight, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		target, known := targets[strings.TrimSpace(name)]
+		if !ok || !known {
+			return weights, errors.New("invalid ranking weights: unknown entry " + pair)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(rawWeight), 64)
+		if err != nil || weight < 0 {
+			return weights, errors.New("invalid ranking weights: " + name + " must be a non-negative number")
+		}
+		*target = weight
+	}
+	return weights, nil
+}
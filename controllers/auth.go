@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
 	"github.com/GradiyantoS/go-dealls-test-app/models"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
 	"github.com/GradiyantoS/go-dealls-test-app/utils"
@@ -12,31 +14,38 @@ import (
 type AuthController interface {
 	SignUp(w http.ResponseWriter, r *http.Request)
 	Login(w http.ResponseWriter, r *http.Request)
+	RefreshToken(w http.ResponseWriter, r *http.Request)
+	Logout(w http.ResponseWriter, r *http.Request)
 }
 
 // authController is the concrete implementation of AuthController
 type authController struct {
-	userService services.UserService
+	userService  services.UserService
+	tokenService services.TokenService
 }
 
 // NewAuthController creates a new AuthController
-func NewAuthController(userService services.UserService) AuthController {
-	return &authController{userService}
+func NewAuthController(userService services.UserService, tokenService services.TokenService) AuthController {
+	return &authController{userService, tokenService}
 }
 
 // SignUpHandler handles user registration
 func (c *authController) SignUp(w http.ResponseWriter, r *http.Request) {
-	var user models.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var req models.SignUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.ErrorResponse(w, http.StatusBadRequest, "invalid request payload")
 		return
 	}
 
-	err := c.userService.SignUp(&user)
-	if err != nil {
+	err := c.userService.SignUp(req.User())
+	if errors.Is(err, services.ErrEmailAlreadyExists) || errors.Is(err, services.ErrPhoneAlreadyExists) {
 		utils.ErrorResponse(w, http.StatusConflict, err.Error())
 		return
 	}
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	utils.DataSuccessResponse(w, http.StatusCreated, map[string]string{"message": "user has been added"})
 }
@@ -49,16 +58,67 @@ func (c *authController) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := c.userService.Login(creds)
+	tokens, err := c.userService.Login(creds)
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
 
-	response := map[string]string{
-		"message": "login success",
-		"token":   token,
+	utils.DataSuccessResponse(w, http.StatusOK, tokenResponse("login success", tokens))
+}
+
+// RefreshToken exchanges a refresh token for a new access and refresh token
+func (c *authController) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.RefreshToken == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	tokens, err := c.tokenService.RefreshTokens(input.RefreshToken)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
 	}
 
-	utils.DataSuccessResponse(w, http.StatusCreated, response)
+	utils.DataSuccessResponse(w, http.StatusOK, tokenResponse("token refreshed", tokens))
+}
+
+// Logout revokes the current access token and the given refresh token
+func (c *authController) Logout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+	claims, _ := middlewares.GetTokenClaimsFromContext(r)
+
+	// The refresh token is optional, an empty body only revokes the access token
+	var input struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "invalid request payload")
+			return
+		}
+	}
+
+	if err := c.tokenService.Logout(userID, claims, input.RefreshToken); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, map[string]string{"message": "logout success"})
+}
+
+func tokenResponse(message string, tokens *models.TokenPair) map[string]interface{} {
+	return map[string]interface{}{
+		"message":       message,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	}
 }
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/gorilla/mux"
+)
+
+type ConversationController interface {
+	SendMessage(w http.ResponseWriter, r *http.Request)
+	GetMessages(w http.ResponseWriter, r *http.Request)
+}
+
+type conversationController struct {
+	chatService services.ChatService
+}
+
+func NewConversationController(chatService services.ChatService) ConversationController {
+	return &conversationController{chatService}
+}
+
+type sendMessageRequest struct {
+	Body string `json:"body"`
+}
+
+func (c *conversationController) SendMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	conversationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid conversation ID")
+		return
+	}
+
+	var request sendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	message, err := c.chatService.SendMessage(userID, conversationID, request.Body)
+	switch {
+	case errors.Is(err, services.ErrInvalidMessage):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, services.ErrConversationNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to send message")
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusCreated, dto.NewMessage(message))
+}
+
+func (c *conversationController) GetMessages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	conversationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid conversation ID")
+		return
+	}
+
+	limit := services.DefaultMessagePageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > services.MaxMessagePageSize {
+			utils.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", services.MaxMessagePageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := c.chatService.GetMessages(userID, conversationID, r.URL.Query().Get("cursor"), limit)
+	switch {
+	case errors.Is(err, services.ErrInvalidCursor):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, services.ErrConversationNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve messages")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(w, http.StatusOK, dto.NewMessages(page.Messages), page.NextCursor)
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/gorilla/mux"
+)
+
+type MatchController interface {
+	GetMatches(w http.ResponseWriter, r *http.Request)
+	Unmatch(w http.ResponseWriter, r *http.Request)
+}
+
+type matchController struct {
+	matchService services.MatchService
+}
+
+func NewMatchController(matchService services.MatchService) MatchController {
+	return &matchController{matchService}
+}
+
+func (c *matchController) GetMatches(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	matches, err := c.matchService.GetMatches(userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve matches")
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewMatches(matches))
+}
+
+func (c *matchController) Unmatch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	matchID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid match ID")
+		return
+	}
+
+	if err := c.matchService.Unmatch(userID, matchID); err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, map[string]string{"message": "Unmatched successfully"})
+}
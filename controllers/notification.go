@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+)
+
+type NotificationController interface {
+	GetNotifications(w http.ResponseWriter, r *http.Request)
+	MarkRead(w http.ResponseWriter, r *http.Request)
+}
+
+type notificationController struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationController(notificationService services.NotificationService) NotificationController {
+	return &notificationController{notificationService}
+}
+
+// markReadRequest says which notifications to mark as read, either the listed IDs or all of them
+type markReadRequest struct {
+	IDs []int `json:"ids"`
+	All bool  `json:"all"`
+}
+
+func (c *notificationController) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	limit := services.DefaultNotificationPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > services.MaxNotificationPageSize {
+			utils.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", services.MaxNotificationPageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := c.notificationService.GetNotifications(userID, r.URL.Query().Get("cursor"), limit)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve notifications")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"notifications": dto.NewNotifications(page.Notifications),
+		"unread_count":  page.UnreadCount,
+	}, page.NextCursor)
+}
+
+func (c *notificationController) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	// Marking everything has to be asked for with {"all": true}, an empty body is rejected
+	var request markReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if request.All && request.IDs != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "ids and all can't be combined")
+		return
+	}
+
+	var unread int
+	var err error
+	if request.All {
+		unread, err = c.notificationService.MarkAllRead(userID)
+	} else {
+		unread, err = c.notificationService.MarkRead(userID, request.IDs)
+	}
+	if errors.Is(err, services.ErrNoNotificationIDs) {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to mark notifications as read")
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, map[string]interface{}{"unread_count": unread})
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+)
+
+// maxWebhookBodySize caps the webhook payload, provider callbacks are tiny
+const maxWebhookBodySize = 64 << 10
+
+type PaymentController interface {
+	PurchasePremium(w http.ResponseWriter, r *http.Request)
+	PaymentWebhook(w http.ResponseWriter, r *http.Request)
+}
+
+type paymentController struct {
+	paymentService services.PaymentService
+}
+
+func NewPaymentController(paymentService services.PaymentService) PaymentController {
+	return &paymentController{paymentService}
+}
+
+func (c *paymentController) PurchasePremium(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	var input struct {
+		PlanID string `json:"plan_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if input.PlanID == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	purchase, err := c.paymentService.StartPurchase(userID, input.PlanID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusAccepted, map[string]interface{}{
+		"message":  "Purchase created, premium features are enabled once the payment is confirmed",
+		"purchase": dto.NewPurchase(purchase),
+	})
+}
+
+func (c *paymentController) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	err = c.paymentService.HandleWebhook(payload, r.Header.Get(payments.SignatureHeader))
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, map[string]string{"message": "Webhook processed"})
+}
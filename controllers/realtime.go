@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/events"
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	websocketWriteWait = 10 * time.Second
+	// Clients must answer a ping within websocketPongWait, pings are sent a bit more often
+	websocketPongWait   = 60 * time.Second
+	websocketPingPeriod = websocketPongWait * 9 / 10
+	// Clients only send control frames
+	websocketReadLimit = 512
+
+	// sseHeartbeatPeriod keeps proxies from closing idle event streams
+	sseHeartbeatPeriod = 30 * time.Second
+)
+
+type RealtimeController interface {
+	WebSocket(w http.ResponseWriter, r *http.Request)
+	Events(w http.ResponseWriter, r *http.Request)
+}
+
+type realtimeController struct {
+	hub      *events.Hub
+	upgrader websocket.Upgrader
+}
+
+func NewRealtimeController(hub *events.Hub) RealtimeController {
+	return &realtimeController{hub: hub}
+}
+
+// WebSocket pushes the user's events as JSON messages until the client disconnects or the
+// access token the connection was opened with expires
+func (c *realtimeController) WebSocket(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	expired, stop := tokenExpiry(r)
+	defer stop()
+
+	// Subscribing first means no event is missed once the client sees the connection open
+	subscription := c.hub.Subscribe(userID)
+	defer subscription.Close()
+
+	conn, err := c.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // The upgrader already replied with an error
+	}
+	defer conn.Close()
+
+	// Reading processes the pongs and notices when the client goes away
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		conn.SetReadLimit(websocketReadLimit)
+		conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(websocketPingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				closeWebSocket(conn, websocket.CloseTryAgainLater, "too many pending events")
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteWait)); err != nil {
+				return
+			}
+		case <-expired:
+			closeWebSocket(conn, websocket.ClosePolicyViolation, "access token expired")
+			return
+		case <-disconnected:
+			return
+		}
+	}
+}
+
+// Events streams the user's events as Server-Sent Events until the client disconnects or the
+// access token the stream was opened with expires. A reconnecting client sending Last-Event-ID
+// first gets the kept events it missed.
+func (c *realtimeController) Events(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	var subscription *events.Subscription
+	var missed []models.Event
+	if value := r.Header.Get("Last-Event-ID"); value != "" {
+		lastEventID, err := strconv.Atoi(value)
+		if err != nil || lastEventID < 0 {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Last-Event-ID")
+			return
+		}
+		subscription, missed = c.hub.Resume(userID, lastEventID)
+	} else {
+		subscription = c.hub.Subscribe(userID)
+	}
+	defer subscription.Close()
+
+	expired, stop := tokenExpiry(r)
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	for _, event := range missed {
+		if err := writeServerSentEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatPeriod)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return // Too many pending events, the client reconnects and resumes
+			}
+			if err := writeServerSentEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-expired:
+			return
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeServerSentEvent(w http.ResponseWriter, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// tokenExpiry fires when the access token the request was authenticated with expires, stop
+// releases the timer
+func tokenExpiry(r *http.Request) (<-chan time.Time, func()) {
+	claims, ok := middlewares.GetTokenClaimsFromContext(r)
+	if !ok || claims.ExpiresAt.IsZero() {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(time.Until(claims.ExpiresAt))
+	return timer.C, func() { timer.Stop() }
+}
+
+func closeWebSocket(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(websocketWriteWait))
+}
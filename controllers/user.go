@@ -2,8 +2,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
 	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
 	"github.com/GradiyantoS/go-dealls-test-app/models"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
@@ -11,9 +15,15 @@ import (
 )
 
 type UserController interface {
-	PurchasePremium(w http.ResponseWriter, r *http.Request)
+	GetProfile(w http.ResponseWriter, r *http.Request)
+	UpdateProfile(w http.ResponseWriter, r *http.Request)
+	UpdateLocation(w http.ResponseWriter, r *http.Request)
+	BoostProfile(w http.ResponseWriter, r *http.Request)
+	GetPlans(w http.ResponseWriter, r *http.Request)
 	SwipeCandidates(w http.ResponseWriter, r *http.Request)
 	SwipeHandler(w http.ResponseWriter, r *http.Request)
+	GetReceivedLikes(w http.ResponseWriter, r *http.Request)
+	RewindSwipe(w http.ResponseWriter, r *http.Request)
 }
 
 type userController struct {
@@ -25,34 +35,102 @@ func NewUserController(userService services.UserService, swipeService services.S
 	return &userController{userService, swipeService}
 }
 
-func (c *userController) PurchasePremium(w http.ResponseWriter, r *http.Request) {
+func (c *userController) GetProfile(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middlewares.GetUserIDFromContext(r)
 	if !ok {
 		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
 		return
 	}
 
-	var input struct {
-		Duration int      `json:"duration"` // Duration in days
-		Features []string `json:"features"` // List of features to enable
+	user, err := c.userService.GetProfile(userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewSelfProfile(user))
+}
+
+func (c *userController) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	var update models.ProfileUpdate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Email, password and premium state can't be changed here
+	if err := decoder.Decode(&update); err != nil {
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 
-	if input.Duration <= 0 {
-		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid duration")
+	user, err := c.userService.UpdateProfile(userID, update)
+	if errors.Is(err, services.ErrPhoneAlreadyExists) {
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
 		return
 	}
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewSelfProfile(user))
+}
 
-	err := c.userService.EnablePremiumFeature(userID, input.Duration, input.Features)
+func (c *userController) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	// Pointers tell a missing coordinate apart from 0, which is a valid one
+	var request struct {
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if request.Latitude == nil || request.Longitude == nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "latitude and longitude are required")
+		return
+	}
+
+	user, err := c.userService.UpdateLocation(userID, models.Location{Latitude: *request.Latitude, Longitude: *request.Longitude})
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.DataSuccessResponse(w, http.StatusOK, map[string]string{"message": "Premium features enabled successfully"})
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewSelfProfile(user))
+}
+
+func (c *userController) BoostProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	boostedUntil, err := c.userService.BoostProfile(userID)
+	switch {
+	case errors.Is(err, services.ErrBoostActive), errors.Is(err, services.ErrBoostCoolingDown):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, map[string]interface{}{"boosted_until": boostedUntil})
+}
+
+func (c *userController) GetPlans(w http.ResponseWriter, r *http.Request) {
+	utils.DataSuccessResponse(w, http.StatusOK, c.userService.GetPlans())
 }
 
 func (c *userController) SwipeCandidates(w http.ResponseWriter, r *http.Request) {
@@ -62,13 +140,27 @@ func (c *userController) SwipeCandidates(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	candidates, err := c.swipeService.GetSwipeCandidates(userID)
+	limit := services.DefaultCandidatePageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > services.MaxCandidatePageSize {
+			utils.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", services.MaxCandidatePageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := c.swipeService.GetSwipeCandidates(userID, r.URL.Query().Get("cursor"), limit)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve swipe candidates")
 		return
 	}
 
-	utils.DataSuccessResponse(w, http.StatusOK, candidates)
+	utils.PaginatedSuccessResponse(w, http.StatusOK, dto.NewCandidateProfiles(page.Candidates), page.NextCursor)
 }
 
 func (c *userController) SwipeHandler(w http.ResponseWriter, r *http.Request) {
@@ -85,11 +177,65 @@ func (c *userController) SwipeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	swipe.UserID = userID
-	err := c.swipeService.RecordSwipe(&swipe)
+	match, err := c.swipeService.RecordSwipe(&swipe)
+	if errors.Is(err, services.ErrInvalidSwipeAction) {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
 		return
 	}
 
-	utils.DataSuccessResponse(w, http.StatusOK, map[string]string{"message": "Swipe recorded successfully"})
+	response := map[string]interface{}{
+		"message":  "Swipe recorded successfully",
+		"is_match": match != nil,
+	}
+	if match != nil {
+		response["match"] = dto.NewMatch(match)
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, response)
+}
+
+func (c *userController) GetReceivedLikes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	received, err := c.swipeService.GetReceivedLikes(userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve received likes")
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewReceivedLikes(received))
+}
+
+func (c *userController) RewindSwipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	swipe, err := c.swipeService.RewindLastSwipe(userID)
+	switch {
+	case errors.Is(err, services.ErrNothingToRewind):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, services.ErrRewindMatched):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Swipe rewound successfully",
+		"swipe":   dto.NewSwipe(swipe),
+	})
 }
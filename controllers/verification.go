@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/gorilla/mux"
+)
+
+// maxVerificationFormSize leaves room for the multipart framing around the document
+const maxVerificationFormSize = services.MaxVerificationDocumentSize + 64<<10
+
+type VerificationController interface {
+	SubmitVerification(w http.ResponseWriter, r *http.Request)
+	GetVerificationStatus(w http.ResponseWriter, r *http.Request)
+	GetPendingVerifications(w http.ResponseWriter, r *http.Request)
+	GetVerificationDocument(w http.ResponseWriter, r *http.Request)
+	ReviewVerification(w http.ResponseWriter, r *http.Request)
+}
+
+type verificationController struct {
+	verificationService services.VerificationService
+}
+
+func NewVerificationController(verificationService services.VerificationService) VerificationController {
+	return &verificationController{verificationService}
+}
+
+// SubmitVerification takes a multipart form with a document_type field and the document file
+func (c *verificationController) SubmitVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxVerificationFormSize)
+	if err := r.ParseMultipartForm(maxVerificationFormSize); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid upload, send a multipart form of at most 5 MB")
+		return
+	}
+	file, _, err := r.FormFile("document")
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "document is required")
+		return
+	}
+	defer file.Close()
+
+	document, err := io.ReadAll(io.LimitReader(file, services.MaxVerificationDocumentSize+1))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid upload")
+		return
+	}
+
+	request, err := c.verificationService.SubmitVerification(userID, r.FormValue("document_type"), document)
+	switch {
+	case errors.Is(err, services.ErrAlreadyVerified), errors.Is(err, services.ErrVerificationPending):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusAccepted, dto.NewVerificationRequest(request))
+}
+
+func (c *verificationController) GetVerificationStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.GetUserIDFromContext(r)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+		return
+	}
+
+	request, err := c.verificationService.GetVerificationStatus(userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewVerificationRequest(request))
+}
+
+func (c *verificationController) GetPendingVerifications(w http.ResponseWriter, r *http.Request) {
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewAdminVerificationQueue(c.verificationService.GetPendingVerifications()))
+}
+
+// GetVerificationDocument serves the uploaded file so an administrator can review it
+func (c *verificationController) GetVerificationDocument(w http.ResponseWriter, r *http.Request) {
+	verificationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid verification ID")
+		return
+	}
+
+	request, err := c.verificationService.GetVerification(verificationID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", request.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(request.Document)
+}
+
+func (c *verificationController) ReviewVerification(w http.ResponseWriter, r *http.Request) {
+	verificationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid verification ID")
+		return
+	}
+
+	var input struct {
+		Decision string `json:"decision"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	request, err := c.verificationService.ReviewVerification(verificationID, input.Decision, input.Reason)
+	switch {
+	case errors.Is(err, services.ErrVerificationNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, services.ErrVerificationReviewed):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.DataSuccessResponse(w, http.StatusOK, dto.NewAdminVerificationRequest(request, nil))
+}
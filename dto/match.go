@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// Match is a mutual like, its ID is also the ID of the conversation between the two users
+type Match struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	MatchedUserID int       `json:"matched_user_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewMatch maps a match
+func NewMatch(match *models.Match) Match {
+	return Match{
+		ID:            match.ID,
+		UserID:        match.UserID,
+		MatchedUserID: match.MatchedID,
+		CreatedAt:     match.CreatedAt,
+	}
+}
+
+// NewMatches maps the matches of a user
+func NewMatches(matches []models.Match) []Match {
+	result := make([]Match, 0, len(matches))
+	for i := range matches {
+		result = append(result, NewMatch(&matches[i]))
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// Message is a chat message as shown to the two users of the conversation
+type Message struct {
+	ID             int       `json:"id"`
+	ConversationID int       `json:"conversation_id"`
+	SenderID       int       `json:"sender_id"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewMessage maps a chat message
+func NewMessage(message *models.Message) Message {
+	return Message{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Body:           message.Body,
+		CreatedAt:      message.CreatedAt,
+	}
+}
+
+// NewMessages maps a page of chat messages
+func NewMessages(messages []models.Message) []Message {
+	result := make([]Message, 0, len(messages))
+	for i := range messages {
+		result = append(result, NewMessage(&messages[i]))
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// Notification is an entry of the user's notification center
+type Notification struct {
+	ID        int                    `json:"id"`
+	Type      string                 `json:"type"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Read      bool                   `json:"read"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewNotification maps a notification
+func NewNotification(notification *models.Notification) Notification {
+	return Notification{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Data:      notification.Data,
+		Read:      notification.Read,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+// NewNotifications maps a page of notifications
+func NewNotifications(notifications []models.Notification) []Notification {
+	result := make([]Notification, 0, len(notifications))
+	for i := range notifications {
+		result = append(result, NewNotification(&notifications[i]))
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// Purchase is a plan purchase as shown to the buyer, the charge ID identifies the payment at the
+// provider
+type Purchase struct {
+	ID            int       `json:"id"`
+	PlanID        string    `json:"plan_id"`
+	ChargeID      string    `json:"charge_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Status        string    `json:"status"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewPurchase maps a purchase for the buyer
+func NewPurchase(purchase *models.Purchase) Purchase {
+	return Purchase{
+		ID:            purchase.ID,
+		PlanID:        purchase.PlanID,
+		ChargeID:      purchase.ChargeID,
+		Amount:        purchase.Amount,
+		Currency:      purchase.Currency,
+		Status:        purchase.Status,
+		FailureReason: purchase.FailureReason,
+		CreatedAt:     purchase.CreatedAt,
+		UpdatedAt:     purchase.UpdatedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// Swipe is a swipe as shown to the user who made it
+type Swipe struct {
+	ID           int       `json:"id"`
+	TargetUserID int       `json:"target_user_id"`
+	Action       string    `json:"action"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewSwipe maps a swipe for the user who made it
+func NewSwipe(swipe *models.Swipe) Swipe {
+	return Swipe{
+		ID:           swipe.ID,
+		TargetUserID: swipe.TargetUserID,
+		Action:       swipe.Action,
+		CreatedAt:    swipe.CreatedAt,
+	}
+}
@@ -0,0 +1,147 @@
+// Package dto holds the shapes the API sends to clients. Controllers map models into these
+// instead of serialising models directly, so credentials and private data can't leak by accident.
+package dto
+
+import (
+	"math"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// PublicProfile is what other users get to see, e.g. in the swipe deck
+type PublicProfile struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Gender     string   `json:"gender"`
+	Age        int      `json:"age,omitempty"`
+	Bio        string   `json:"bio"`
+	Occupation string   `json:"occupation"`
+	Interests  []string `json:"interests"`
+	IsVerified bool     `json:"is_verified"`
+}
+
+// CandidateProfile is a public profile in the swipe deck
+type CandidateProfile struct {
+	PublicProfile
+	DistanceKm    *int `json:"distance_km,omitempty"`
+	SuperLikedYou bool `json:"super_liked_you"`
+}
+
+// ReceivedLike is a like waiting for an answer, blurred likes leave out who sent it and when
+type ReceivedLike struct {
+	Profile   *PublicProfile `json:"profile,omitempty"`
+	SuperLike bool           `json:"super_like"`
+	LikedAt   *time.Time     `json:"liked_at,omitempty"`
+}
+
+// ReceivedLikes is the "who liked me" inbox
+type ReceivedLikes struct {
+	Count   int            `json:"count"`
+	Blurred bool           `json:"blurred"`
+	Likes   []ReceivedLike `json:"likes"`
+}
+
+// SelfProfile is the user's own profile, including contact details and premium state
+type SelfProfile struct {
+	PublicProfile
+	Email           string                 `json:"email"`
+	Phone           string                 `json:"phone"`
+	Location        *models.Location       `json:"location"`
+	Birthdate       models.Date            `json:"birthdate"`
+	Preferences     models.Preferences     `json:"preferences"`
+	PremiumExpiry   *time.Time             `json:"premium_expiry"`
+	PremiumFeatures models.PremiumFeatures `json:"premium_features"`
+	// PremiumFeatureExpiry is when each feature runs out, renewals only extend the features bought
+	PremiumFeatureExpiry models.PremiumFeatureExpiry `json:"premium_feature_expiry"`
+	CreatedAt            time.Time                   `json:"created_at"`
+	UpdatedAt            time.Time                   `json:"updated_at"`
+}
+
+// AdminUser is the back-office view of an account
+type AdminUser struct {
+	SelfProfile
+	IsInactive bool `json:"is_inactive"`
+}
+
+// NewPublicProfile maps a user to the profile shown to other users
+func NewPublicProfile(user *models.User) PublicProfile {
+	interests := user.Interests
+	if interests == nil {
+		interests = []string{}
+	}
+	return PublicProfile{
+		ID:         user.ID,
+		Name:       user.Name,
+		Gender:     user.Gender,
+		Age:        user.Birthdate.AgeAt(time.Now()),
+		Bio:        user.Bio,
+		Occupation: user.Occupation,
+		Interests:  interests,
+		IsVerified: user.IsVerified,
+	}
+}
+
+// NewPublicProfiles maps a list of users to public profiles
+func NewPublicProfiles(users []models.User) []PublicProfile {
+	profiles := make([]PublicProfile, 0, len(users))
+	for i := range users {
+		profiles = append(profiles, NewPublicProfile(&users[i]))
+	}
+	return profiles
+}
+
+// NewCandidateProfiles maps swipe candidates to public profiles, distances are rounded up to whole
+// kilometres so the exact location of other users can't be worked out
+func NewCandidateProfiles(candidates []models.Candidate) []CandidateProfile {
+	profiles := make([]CandidateProfile, 0, len(candidates))
+	for i := range candidates {
+		profile := CandidateProfile{PublicProfile: NewPublicProfile(&candidates[i].User), SuperLikedYou: candidates[i].SuperLikedYou}
+		if candidates[i].DistanceKm != nil {
+			distance := int(math.Max(1, math.Ceil(*candidates[i].DistanceKm)))
+			profile.DistanceKm = &distance
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// NewReceivedLikes maps the likes inbox, likes the user isn't allowed to see are blurred
+func NewReceivedLikes(received *models.ReceivedLikes) ReceivedLikes {
+	inbox := ReceivedLikes{Count: len(received.Likes), Blurred: !received.Visible, Likes: make([]ReceivedLike, 0, len(received.Likes))}
+	for i := range received.Likes {
+		like := ReceivedLike{SuperLike: received.Likes[i].SuperLike}
+		if received.Visible && received.Likes[i].User != nil {
+			profile := NewPublicProfile(received.Likes[i].User)
+			like.Profile = &profile
+			like.LikedAt = &received.Likes[i].LikedAt
+		}
+		inbox.Likes = append(inbox.Likes, like)
+	}
+	return inbox
+}
+
+// NewSelfProfile maps a user to the profile returned to that same user
+func NewSelfProfile(user *models.User) SelfProfile {
+	return SelfProfile{
+		PublicProfile:        NewPublicProfile(user),
+		Email:                user.Email,
+		Phone:                user.Phone,
+		Location:             user.Location,
+		Birthdate:            user.Birthdate,
+		Preferences:          user.Preferences,
+		PremiumExpiry:        user.PremiumExpiry,
+		PremiumFeatures:      user.PremiumFeatures,
+		PremiumFeatureExpiry: user.FeatureExpiry(),
+		CreatedAt:            user.CreatedAt,
+		UpdatedAt:            user.UpdatedAt,
+	}
+}
+
+// NewAdminUser maps a user to the view used by administrators
+func NewAdminUser(user *models.User) AdminUser {
+	return AdminUser{
+		SelfProfile: NewSelfProfile(user),
+		IsInactive:  user.IsInactive,
+	}
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// VerificationRequest is a verification request as shown to the user who sent it
+type VerificationRequest struct {
+	ID           int        `json:"id"`
+	DocumentType string     `json:"document_type"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// AdminVerificationRequest is the back-office view of a verification request, the document
+// itself is served separately
+type AdminVerificationRequest struct {
+	VerificationRequest
+	UserID      int        `json:"user_id"`
+	ContentType string     `json:"content_type"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty"`
+	User        *AdminUser `json:"user,omitempty"`
+}
+
+// NewVerificationRequest maps a verification request for the user who sent it
+func NewVerificationRequest(request *models.VerificationRequest) VerificationRequest {
+	return VerificationRequest{
+		ID:           request.ID,
+		DocumentType: request.DocumentType,
+		Status:       request.Status,
+		Reason:       request.Reason,
+		CreatedAt:    request.CreatedAt,
+		ReviewedAt:   request.ReviewedAt,
+	}
+}
+
+// NewAdminVerificationRequest maps a verification request for administrators, user is the
+// account that sent it and may be nil
+func NewAdminVerificationRequest(request *models.VerificationRequest, user *models.User) AdminVerificationRequest {
+	adminRequest := AdminVerificationRequest{
+		VerificationRequest: NewVerificationRequest(request),
+		UserID:              request.UserID,
+		ContentType:         request.ContentType,
+		ReviewedBy:          request.ReviewedBy,
+	}
+	if user != nil {
+		adminUser := NewAdminUser(user)
+		adminRequest.User = &adminUser
+	}
+	return adminRequest
+}
+
+// NewAdminVerificationQueue maps the review queue
+func NewAdminVerificationQueue(pending []models.PendingVerification) []AdminVerificationRequest {
+	queue := make([]AdminVerificationRequest, 0, len(pending))
+	for i := range pending {
+		queue = append(queue, NewAdminVerificationRequest(&pending[i].Request, pending[i].User))
+	}
+	return queue
+}
@@ -0,0 +1,119 @@
+// Package events delivers account events to the clients connected to this instance.
+package events
+
+import (
+	"sync"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+const (
+	// subscriptionBuffer is how many events may wait for a slow connection before it is dropped
+	subscriptionBuffer = 32
+	// historySize is how many of the latest events of each user are kept for resuming streams
+	historySize = 50
+)
+
+// Hub numbers the published events, keeps the latest ones of every user and fans them out to
+// every live subscription of their user
+type Hub struct {
+	mu            sync.Mutex
+	subscriptions map[int]map[*Subscription]struct{}
+	history       map[int][]models.Event // oldest first
+	lastEventID   int
+}
+
+// Subscription receives the events of one user until it is closed
+type Subscription struct {
+	UserID int
+	events chan models.Event
+	hub    *Hub
+	closed bool // guarded by hub.mu
+}
+
+// NewHub creates a Hub without subscriptions
+func NewHub() *Hub {
+	return &Hub{
+		subscriptions: make(map[int]map[*Subscription]struct{}),
+		history:       make(map[int][]models.Event),
+	}
+}
+
+// Subscribe starts receiving the events of the user, a user may hold several subscriptions
+func (h *Hub) Subscribe(userID int) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.subscribe(userID)
+}
+
+// Resume subscribes like Subscribe and returns the kept events of the user published after
+// lastEventID, oldest first. Older events than the kept ones are lost.
+func (h *Hub) Resume(userID int, lastEventID int) (*Subscription, []models.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var missed []models.Event
+	for _, event := range h.history[userID] {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+	return h.subscribe(userID), missed
+}
+
+// Publish numbers the event and hands it to every subscription of its user without waiting for
+// them. A subscription that can't keep up is closed, its client has to reconnect.
+func (h *Hub) Publish(event models.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastEventID++
+	event.ID = h.lastEventID
+
+	history := append(h.history[event.UserID], event)
+	if len(history) > historySize {
+		history = append([]models.Event(nil), history[len(history)-historySize:]...)
+	}
+	h.history[event.UserID] = history
+
+	for subscription := range h.subscriptions[event.UserID] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+// Events is closed once the subscription is closed
+func (s *Subscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// Close stops the subscription, closing it more than once is fine
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// subscribe must be called with mu held
+func (h *Hub) subscribe(userID int) *Subscription {
+	subscription := &Subscription{UserID: userID, events: make(chan models.Event, subscriptionBuffer), hub: h}
+	if h.subscriptions[userID] == nil {
+		h.subscriptions[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscriptions[userID][subscription] = struct{}{}
+	return subscription
+}
+
+// remove must be called with mu held
+func (h *Hub) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	close(subscription.events)
+	delete(h.subscriptions[subscription.UserID], subscription)
+	if len(h.subscriptions[subscription.UserID]) == 0 {
+		delete(h.subscriptions, subscription.UserID)
+	}
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminKeyHeader is the request header carrying the administrator API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminMiddleware only lets requests through that carry the administrator API key,
+// an empty key disables the admin endpoints altogether
+func AdminMiddleware(apiKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(AdminKeyHeader)
+			if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+				http.Error(w, "Invalid admin key", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
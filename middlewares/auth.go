@@ -12,32 +12,63 @@ import (
 type contextKey string
 
 const UserContextKey contextKey = "user_id"
+const TokenClaimsContextKey contextKey = "token_claims"
 
-// AuthMiddleware validates the JWT and adds the user ID to the request context
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header is missing", http.StatusUnauthorized)
-			return
-		}
-
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == authHeader {
-			http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
-			return
-		}
-
-		userID, err := utils.ValidateJWT(token)
-		if err != nil {
-			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-			return
-		}
-
-		// Add the user ID to the request context
-		r = r.WithContext(context.WithValue(r.Context(), UserContextKey, userID))
-		next.ServeHTTP(w, r)
-	})
+// TokenRevocationChecker reports whether an access token has been revoked before its expiry
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(jti string) bool
+}
+
+// AccessTokenQueryParam carries the access token of stream connections, browsers can't set headers
+// on WebSocket and EventSource requests
+const AccessTokenQueryParam = "access_token"
+
+// AuthMiddleware validates the JWT, rejects revoked tokens and adds the user ID to the request context
+func AuthMiddleware(revocations TokenRevocationChecker) func(http.Handler) http.Handler {
+	return authMiddleware(revocations, false)
+}
+
+// StreamAuthMiddleware is AuthMiddleware for long lived connections, the token may also be
+// passed in the access_token query parameter
+func StreamAuthMiddleware(revocations TokenRevocationChecker) func(http.Handler) http.Handler {
+	return authMiddleware(revocations, true)
+}
+
+func authMiddleware(revocations TokenRevocationChecker, allowQueryToken bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" && allowQueryToken && r.URL.Query().Get(AccessTokenQueryParam) != "" {
+				authHeader = "Bearer " + r.URL.Query().Get(AccessTokenQueryParam)
+			}
+			if authHeader == "" {
+				http.Error(w, "Authorization header is missing", http.StatusUnauthorized)
+				return
+			}
+
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if token == authHeader {
+				http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := utils.ParseJWT(token)
+			if err != nil {
+				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				return
+			}
+
+			if claims.ID != "" && revocations.IsAccessTokenRevoked(claims.ID) {
+				http.Error(w, "Token has been revoked", http.StatusUnauthorized)
+				return
+			}
+
+			// Add the user ID and token claims to the request context
+			ctx := context.WithValue(r.Context(), UserContextKey, claims.UserID)
+			ctx = context.WithValue(ctx, TokenClaimsContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 // GetUserIDFromContext retrieves the user ID from the request context
@@ -45,3 +76,9 @@ func GetUserIDFromContext(r *http.Request) (int, bool) {
 	userID, ok := r.Context().Value(UserContextKey).(int)
 	return userID, ok
 }
+
+// GetTokenClaimsFromContext retrieves the claims of the access token used for the request
+func GetTokenClaimsFromContext(r *http.Request) (*utils.TokenClaims, bool) {
+	claims, ok := r.Context().Value(TokenClaimsContextKey).(*utils.TokenClaims)
+	return claims, ok
+}
@@ -0,0 +1,137 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// Idempotency replays the first response stored for a user's Idempotency-Key instead of running
+// the handler again, so client retries can't apply the same action twice. It must run after
+// AuthMiddleware, requests without the header are passed through untouched.
+func Idempotency(store repositories.IdempotencyRepository, ttl time.Duration) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	inFlight := map[string]bool{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				utils.ErrorResponse(w, http.StatusBadRequest, "Idempotency-Key is too long")
+				return
+			}
+
+			userID, ok := GetUserIDFromContext(r)
+			if !ok {
+				utils.ErrorResponse(w, http.StatusUnauthorized, "Failed to retrieve user ID")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentRequestBytes))
+			if err != nil {
+				utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request payload")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := requestFingerprint(r, body)
+
+			// Only one request per key may run at a time, a concurrent retry is told to come back later
+			lockKey := strconv.Itoa(userID) + ":" + key
+			mu.Lock()
+			if inFlight[lockKey] {
+				mu.Unlock()
+				utils.ErrorResponse(w, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+				return
+			}
+			inFlight[lockKey] = true
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				delete(inFlight, lockKey)
+				mu.Unlock()
+			}()
+
+			if record, err := store.GetRecord(userID, key); err == nil {
+				if record.RequestFingerprint != fingerprint {
+					utils.ErrorResponse(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+					return
+				}
+				replayResponse(w, record)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// Server errors are not stored so the client can retry them
+			if recorder.statusCode >= http.StatusInternalServerError {
+				return
+			}
+			store.SaveRecord(&models.IdempotencyRecord{
+				UserID:             userID,
+				Key:                key,
+				RequestFingerprint: fingerprint,
+				StatusCode:         recorder.statusCode,
+				Header:             recorder.Header().Clone(),
+				Body:               recorder.body.Bytes(),
+				ExpiresAt:          time.Now().Add(ttl),
+			})
+		})
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	sum := sha256.Sum256(body)
+	return r.Method + " " + r.URL.Path + " " + hex.EncodeToString(sum[:])
+}
+
+func replayResponse(w http.ResponseWriter, record *models.IdempotencyRecord) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// responseRecorder passes the response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,155 @@
+package migrations
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Migration is a single versioned schema change, Down must undo exactly what Up did.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied to the database.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+const createStateTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	applied_at DATETIME NOT NULL
+);`
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator running the registered migrations against db.
+func NewMigrator(db *sql.DB) *Migrator {
+	return NewMigratorWith(db, All)
+}
+
+// NewMigratorWith creates a Migrator for a custom set of migrations, they are applied by ascending version.
+func NewMigratorWith(db *sql.DB, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// Up applies every pending migration and returns the ones it applied.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.inTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				migration.Version, migration.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return ran, errors.New("migration " + migration.Name + " failed: " + err.Error())
+		}
+		ran = append(ran, migration)
+	}
+	return ran, nil
+}
+
+// Down rolls back the most recently applied migration, it returns nil when nothing is applied.
+func (m *Migrator) Down() (*Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.inTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return nil, errors.New("rollback of " + migration.Name + " failed: " + err.Error())
+		}
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// Status lists every known migration together with when it was applied.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []MigrationStatus{}
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+func (m *Migrator) appliedVersions() (map[int]time.Time, error) {
+	if _, err := m.db.Exec(createStateTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
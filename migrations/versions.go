@@ -0,0 +1,203 @@
+package migrations
+
+// All holds every schema migration, append new ones with the next version number
+// and never edit a migration once it has been released.
+var All = []Migration{
+	{
+		Version: 1,
+		Name:    "create_users",
+		// IF NOT EXISTS keeps databases created before migrations existed working
+		Up: `
+CREATE TABLE IF NOT EXISTS users (
+	id               INTEGER PRIMARY KEY,
+	email            TEXT NOT NULL UNIQUE,
+	password         TEXT NOT NULL,
+	phone            TEXT NOT NULL UNIQUE,
+	name             TEXT NOT NULL,
+	gender           TEXT NOT NULL,
+	is_inactive      BOOLEAN NOT NULL DEFAULT 0,
+	premium_expiry   DATETIME,
+	unlimited_swipes BOOLEAN NOT NULL DEFAULT 0,
+	is_verified      BOOLEAN NOT NULL DEFAULT 0,
+	created_at       DATETIME NOT NULL,
+	updated_at       DATETIME NOT NULL
+);`,
+		Down: `DROP TABLE users;`,
+	},
+	{
+		Version: 2,
+		Name:    "create_swipes",
+		Up: `
+CREATE TABLE IF NOT EXISTS swipes (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id        INTEGER NOT NULL REFERENCES users(id),
+	target_user_id INTEGER NOT NULL REFERENCES users(id),
+	action         TEXT NOT NULL,
+	created_at     DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_swipes_user_id ON swipes(user_id);`,
+		Down: `
+DROP INDEX idx_swipes_user_id;
+DROP TABLE swipes;`,
+	},
+	{
+		Version: 3,
+		Name:    "add_user_profile_fields",
+		Up: `
+ALTER TABLE users ADD COLUMN bio TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN occupation TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN interests TEXT NOT NULL DEFAULT '[]';`,
+		Down: `
+ALTER TABLE users DROP COLUMN interests;
+ALTER TABLE users DROP COLUMN occupation;
+ALTER TABLE users DROP COLUMN bio;`,
+	},
+	{
+		Version: 4,
+		Name:    "add_user_preferences",
+		Up: `
+ALTER TABLE users ADD COLUMN interested_in TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE users ADD COLUMN min_age INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN max_age INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN max_distance_km INTEGER NOT NULL DEFAULT 0;`,
+		Down: `
+ALTER TABLE users DROP COLUMN max_distance_km;
+ALTER TABLE users DROP COLUMN max_age;
+ALTER TABLE users DROP COLUMN min_age;
+ALTER TABLE users DROP COLUMN interested_in;`,
+	},
+	{
+		Version: 5,
+		Name:    "add_user_birthdate",
+		Up:      `ALTER TABLE users ADD COLUMN birthdate TEXT NOT NULL DEFAULT '';`,
+		Down:    `ALTER TABLE users DROP COLUMN birthdate;`,
+	},
+	{
+		Version: 6,
+		Name:    "add_user_location",
+		Up: `
+ALTER TABLE users ADD COLUMN latitude REAL;
+ALTER TABLE users ADD COLUMN longitude REAL;
+ALTER TABLE users ADD COLUMN cell_lat INTEGER;
+ALTER TABLE users ADD COLUMN cell_lng INTEGER;
+CREATE INDEX idx_users_geo_cell ON users (cell_lat, cell_lng);`,
+		Down: `
+DROP INDEX idx_users_geo_cell;
+ALTER TABLE users DROP COLUMN cell_lng;
+ALTER TABLE users DROP COLUMN cell_lat;
+ALTER TABLE users DROP COLUMN longitude;
+ALTER TABLE users DROP COLUMN latitude;`,
+	},
+	{
+		Version: 7,
+		Name:    "add_swipes_target_user_index",
+		Up:      `CREATE INDEX idx_swipes_target_user_id ON swipes(target_user_id);`,
+		Down:    `DROP INDEX idx_swipes_target_user_id;`,
+	},
+	{
+		Version: 8,
+		Name:    "separate_verified_and_boosted",
+		// is_verified used to hold the profile boost entitlement
+		Up: `
+ALTER TABLE users RENAME COLUMN is_verified TO profile_boost;
+ALTER TABLE users ADD COLUMN is_verified BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN boosted_until DATETIME;
+CREATE INDEX idx_users_boosted_until ON users (boosted_until);`,
+		Down: `
+DROP INDEX idx_users_boosted_until;
+ALTER TABLE users DROP COLUMN boosted_until;
+ALTER TABLE users DROP COLUMN is_verified;
+ALTER TABLE users RENAME COLUMN profile_boost TO is_verified;`,
+	},
+	{
+		Version: 9,
+		Name:    "add_premium_rewind",
+		Up:      `ALTER TABLE users ADD COLUMN rewind BOOLEAN NOT NULL DEFAULT 0;`,
+		Down:    `ALTER TABLE users DROP COLUMN rewind;`,
+	},
+	{
+		Version: 10,
+		Name:    "create_notifications",
+		Up: `
+CREATE TABLE notifications (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id    INTEGER NOT NULL REFERENCES users(id),
+	type       TEXT NOT NULL,
+	data       TEXT NOT NULL DEFAULT 'null',
+	is_read    BOOLEAN NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, id);`,
+		Down: `
+DROP INDEX idx_notifications_user_id;
+DROP TABLE notifications;`,
+	},
+	{
+		Version: 11,
+		Name:    "add_premium_feature_expiry",
+		// Features bought before keep running until premium_expiry, see models.User.FeatureExpiry
+		Up: `
+ALTER TABLE users ADD COLUMN unlimited_swipes_until DATETIME;
+ALTER TABLE users ADD COLUMN profile_boost_until DATETIME;
+ALTER TABLE users ADD COLUMN rewind_until DATETIME;`,
+		Down: `
+ALTER TABLE users DROP COLUMN rewind_until;
+ALTER TABLE users DROP COLUMN profile_boost_until;
+ALTER TABLE users DROP COLUMN unlimited_swipes_until;`,
+	},
+	{
+		Version: 12,
+		Name:    "create_matches_and_purchases",
+		Up: `
+CREATE TABLE matches (
+	id         INTEGER PRIMARY KEY,
+	user_id    INTEGER NOT NULL REFERENCES users(id),
+	matched_id INTEGER NOT NULL REFERENCES users(id),
+	created_at DATETIME NOT NULL
+);
+
+-- Two users share at most one match, whoever liked first
+CREATE UNIQUE INDEX idx_matches_pair ON matches(MIN(user_id, matched_id), MAX(user_id, matched_id));
+CREATE INDEX idx_matches_matched_id ON matches(matched_id);
+
+CREATE TABLE purchases (
+	id             INTEGER PRIMARY KEY,
+	user_id        INTEGER NOT NULL REFERENCES users(id),
+	plan_id        TEXT NOT NULL,
+	charge_id      TEXT NOT NULL,
+	amount         INTEGER NOT NULL,
+	currency       TEXT NOT NULL,
+	status         TEXT NOT NULL,
+	failure_reason TEXT NOT NULL DEFAULT '',
+	created_at     DATETIME NOT NULL,
+	updated_at     DATETIME NOT NULL
+);
+
+CREATE INDEX idx_purchases_charge_id ON purchases(charge_id);`,
+		Down: `
+DROP INDEX idx_purchases_charge_id;
+DROP TABLE purchases;
+DROP INDEX idx_matches_matched_id;
+DROP INDEX idx_matches_pair;
+DROP TABLE matches;`,
+	},
+	{
+		Version: 13,
+		Name:    "create_unmatches",
+		// Likes made before the users unmatched don't match them again, user_id is the lower ID of the pair
+		Up: `
+CREATE TABLE unmatches (
+	user_id      INTEGER NOT NULL REFERENCES users(id),
+	other_id     INTEGER NOT NULL REFERENCES users(id),
+	unmatched_at DATETIME NOT NULL,
+	PRIMARY KEY (user_id, other_id)
+);
+
+CREATE INDEX idx_unmatches_other_id ON unmatches(other_id);`,
+		Down: `
+DROP INDEX idx_unmatches_other_id;
+DROP TABLE unmatches;`,
+	},
+}
@@ -0,0 +1,15 @@
+package models
+
+// Candidate is a profile in a user's swipe deck together with how it relates to that user
+type Candidate struct {
+	User          User
+	DistanceKm    *float64 // nil when either user hasn't reported a location
+	Score         float64  // ranking score, higher is shown first
+	SuperLikedYou bool     // the candidate super liked the user and is still waiting for an answer
+}
+
+// CandidatePage is one page of a swipe deck, NextCursor is empty on the last page
+type CandidatePage struct {
+	Candidates []Candidate
+	NextCursor string
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Conversation is the chat between the two users of a match, it shares the match's ID
+type Conversation struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	OtherUserID int       `json:"other_user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HasUser reports whether the given user is one of the two sides of the conversation.
+func (c *Conversation) HasUser(userID int) bool {
+	return c.UserID == userID || c.OtherUserID == userID
+}
+
+type Message struct {
+	ID             int       `json:"id"`
+	ConversationID int       `json:"conversation_id"`
+	SenderID       int       `json:"sender_id"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MessagePage is one page of a conversation, newest first. NextCursor is empty on the last page.
+type MessagePage struct {
+	Messages   []Message
+	NextCursor string
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DateLayout is the format dates are exchanged in, e.g. "1995-04-23"
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date without a time of day, it is (un)marshalled as "YYYY-MM-DD"
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of the given year, month and day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a "YYYY-MM-DD" string, an empty string is the zero date
+func ParseDate(value string) (Date, error) {
+	if value == "" {
+		return Date{}, nil
+	}
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{t}, nil
+}
+
+// String formats the date as "YYYY-MM-DD", the zero date is an empty string
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// AgeAt returns the age in whole years of someone born on this date, 0 for the zero date
+func (d Date) AgeAt(now time.Time) int {
+	if d.IsZero() {
+		return 0
+	}
+	age := now.Year() - d.Year()
+	if now.Month() < d.Month() || (now.Month() == d.Month() && now.Day() < d.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	EventLikeReceived     = "like_received"
+	EventMutualLike       = "mutual_like"
+	EventQuotaReset       = "quota_reset"
+	EventPremiumExpiring  = "premium_expiring"
+	EventPremiumActivated = "premium_activated"
+	EventPremiumExpired   = "premium_expired"
+)
+
+// Event is pushed to the live connections of a user when something happens to their account, the
+// ID is assigned when the event is published
+type Event struct {
+	ID        int                    `json:"id"`
+	Type      string                 `json:"type"`
+	UserID    int                    `json:"-"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord is the first response returned for a user's Idempotency-Key
+type IdempotencyRecord struct {
+	UserID             int
+	Key                string
+	RequestFingerprint string // Method, path and body hash of the original request
+	StatusCode         int
+	Header             http.Header
+	Body               []byte
+	ExpiresAt          time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ReceivedLike is a like or super like the user hasn't answered yet
+type ReceivedLike struct {
+	User      *User // nil when the likes are hidden from the user
+	SuperLike bool
+	LikedAt   time.Time
+}
+
+// ReceivedLikes are the likes waiting for the user, newest first. Only premium users get to see
+// who liked them, Visible is false otherwise and the likes carry no user.
+type ReceivedLikes struct {
+	Likes   []ReceivedLike
+	Visible bool
+}
@@ -0,0 +1,22 @@
+package models
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// Location is a point on earth in decimal degrees
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// DistanceKm returns the great-circle distance between two locations using the haversine formula
+func (l Location) DistanceKm(other Location) float64 {
+	lat1 := l.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (other.Longitude - l.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
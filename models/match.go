@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Match struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	MatchedID int       `json:"matched_user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HasUser reports whether the given user is one of the two sides of the match.
+func (m *Match) HasUser(userID int) bool {
+	return m.UserID == userID || m.MatchedID == userID
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Notification is an event kept in the user's notification center, Type is the type of the event
+type Notification struct {
+	ID        int                    `json:"id"`
+	UserID    int                    `json:"-"`
+	Type      string                 `json:"type"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Read      bool                   `json:"read"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NotificationPage is one page of a notification center, newest first. NextCursor is empty on the
+// last page, UnreadCount counts every unread notification of the user.
+type NotificationPage struct {
+	Notifications []Notification
+	NextCursor    string
+	UnreadCount   int
+}
@@ -0,0 +1,11 @@
+package models
+
+// Plan is a purchasable premium bundle from the plan catalogue
+type Plan struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	DurationDays int      `json:"duration_days"`
+	Price        int64    `json:"price"` // In the smallest unit of the currency
+	Currency     string   `json:"currency"`
+	Features     []string `json:"features"`
+}
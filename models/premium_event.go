@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const (
+	PremiumEventExpired  = "premium_expired"
+	PremiumEventExpiring = "premium_expiring"
+)
+
+// PremiumEvent records a change of a user's premium entitlements
+type PremiumEvent struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Type      string     `json:"type"`
+	Features  []string   `json:"features"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // the premium expiry an expiring event warned about
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	PurchaseStatusPending  = "pending"
+	PurchaseStatusPaid     = "paid"
+	PurchaseStatusFailed   = "failed"
+	PurchaseStatusRefunded = "refunded"
+)
+
+// Purchase tracks a plan bought by a user from the charge until premium is activated
+type Purchase struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	PlanID        string    `json:"plan_id"`
+	ChargeID      string    `json:"charge_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Status        string    `json:"status"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
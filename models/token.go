@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RefreshToken is stored server-side, only the hash of the opaque token handed to the client is kept
+type RefreshToken struct {
+	TokenHash string     `json:"-"`
+	UserID    int        `json:"user_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // Access token lifetime in seconds
+}
@@ -4,21 +4,124 @@ import "time"
 
 type PremiumFeatures struct {
 	UnlimitedSwipes bool `json:"unlimited_swipes"`
-	IsVerified      bool `json:"profile_boost"`
+	ProfileBoost    bool `json:"profile_boost"` // allows boosting the profile, see User.BoostedUntil
+	Rewind          bool `json:"rewind"`        // allows undoing the last swipe
+}
+
+// PremiumFeatureExpiry is when each premium feature runs out, a purchase only extends the
+// features of its plan. nil means the feature was never bought.
+type PremiumFeatureExpiry struct {
+	UnlimitedSwipes *time.Time `json:"unlimited_swipes"`
+	ProfileBoost    *time.Time `json:"profile_boost"`
+	Rewind          *time.Time `json:"rewind"`
+}
+
+// Preferences describe who a user wants to see in their deck, zero values mean no limit
+type Preferences struct {
+	InterestedIn  []string `json:"interested_in"`
+	MinAge        int      `json:"min_age"`
+	MaxAge        int      `json:"max_age"`
+	MaxDistanceKm int      `json:"max_distance_km"`
 }
 
 type User struct {
 	ID              int             `json:"id"`
 	Email           string          `json:"email"`
-	Password        string          `json:"password"`
+	Password        string          `json:"-"`
 	Phone           string          `json:"phone"`
 	Name            string          `json:"name"`
 	Gender          string          `json:"gender"` // "male" or "female"
+	Birthdate       Date            `json:"birthdate"`
+	Bio             string          `json:"bio"`
+	Occupation      string          `json:"occupation"`
+	Interests       []string        `json:"interests"`
+	Preferences     Preferences     `json:"preferences"`
+	Location        *Location       `json:"location"`
 	IsInactive      bool            `json:"is_inactive"`
-	PremiumExpiry   *time.Time      `json:"premium_expiry"`
+	IsVerified      bool            `json:"is_verified"`    // earned through verification, can't be bought
+	BoostedUntil    *time.Time      `json:"boosted_until"`  // end of the latest boost window
+	PremiumExpiry   *time.Time      `json:"premium_expiry"` // when the last premium feature runs out
 	PremiumFeatures PremiumFeatures `json:"premium_features"`
-	CreatedAt       time.Time       `json:"created_at"`
-	UpdatedAt       time.Time       `json:"updated_at"`
+	// PremiumFeatureExpiry is when each feature runs out, see FeatureExpiry
+	PremiumFeatureExpiry PremiumFeatureExpiry `json:"premium_feature_expiry"`
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            time.Time            `json:"updated_at"`
+}
+
+// IsBoosted reports whether the user's boost window is open at the given time
+func (u *User) IsBoosted(now time.Time) bool {
+	return u.BoostedUntil != nil && u.BoostedUntil.After(now)
+}
+
+// FeatureExpiry returns when each premium feature runs out. Features enabled before expiries
+// were kept per feature run until PremiumExpiry.
+func (u *User) FeatureExpiry() PremiumFeatureExpiry {
+	expiry := u.PremiumFeatureExpiry
+	legacy := func(until *time.Time, enabled bool) *time.Time {
+		if until == nil && enabled {
+			return u.PremiumExpiry
+		}
+		return until
+	}
+	expiry.UnlimitedSwipes = legacy(expiry.UnlimitedSwipes, u.PremiumFeatures.UnlimitedSwipes)
+	expiry.ProfileBoost = legacy(expiry.ProfileBoost, u.PremiumFeatures.ProfileBoost)
+	expiry.Rewind = legacy(expiry.Rewind, u.PremiumFeatures.Rewind)
+	return expiry
+}
+
+// ActivePremiumFeatures returns the premium features running at the given time
+func (u *User) ActivePremiumFeatures(now time.Time) PremiumFeatures {
+	expiry := u.FeatureExpiry()
+	running := func(until *time.Time) bool {
+		return until != nil && until.After(now)
+	}
+	return PremiumFeatures{
+		UnlimitedSwipes: running(expiry.UnlimitedSwipes),
+		ProfileBoost:    running(expiry.ProfileBoost),
+		Rewind:          running(expiry.Rewind),
+	}
+}
+
+// ProfileUpdate is a partial update of a user's own profile, nil fields are left unchanged
+type ProfileUpdate struct {
+	Name        *string      `json:"name"`
+	Phone       *string      `json:"phone"`
+	Gender      *string      `json:"gender"`
+	Bio         *string      `json:"bio"`
+	Occupation  *string      `json:"occupation"`
+	Interests   *[]string    `json:"interests"`
+	Preferences *Preferences `json:"preferences"`
+}
+
+// SignUpRequest is what a client may set when signing up, anything else on the account
+// (verification, boosts, premium) is decided by the server
+type SignUpRequest struct {
+	Email       string      `json:"email"`
+	Password    string      `json:"password"`
+	Phone       string      `json:"phone"`
+	Name        string      `json:"name"`
+	Gender      string      `json:"gender"`
+	Birthdate   Date        `json:"birthdate"`
+	Bio         string      `json:"bio"`
+	Occupation  string      `json:"occupation"`
+	Interests   []string    `json:"interests"`
+	Preferences Preferences `json:"preferences"`
+}
+
+// User builds the new account described by the request
+func (r SignUpRequest) User() *User {
+	return &User{
+		Email:       r.Email,
+		Password:    r.Password,
+		Phone:       r.Phone,
+		Name:        r.Name,
+		Gender:      r.Gender,
+		Birthdate:   r.Birthdate,
+		Bio:         r.Bio,
+		Occupation:  r.Occupation,
+		Interests:   r.Interests,
+		Preferences: r.Preferences,
+	}
 }
 
 type Credentials struct {
@@ -26,9 +129,28 @@ type Credentials struct {
 	Password   string `json:"password"`
 }
 
+// SwipeActivity summarises the swipes made by and on a user
+type SwipeActivity struct {
+	LikesReceived  int
+	SwipesReceived int
+	LastSwipeAt    time.Time // zero if the user never swiped
+}
+
+const (
+	SwipeActionLike      = "like"
+	SwipeActionPass      = "pass"
+	SwipeActionSuperLike = "super_like"
+)
+
 type Swipe struct {
+	ID           int       `json:"id"`
 	UserID       int       `json:"user_id"`
 	TargetUserID int       `json:"target_user_id"`
-	Action       string    `json:"action"` // "like" or "pass"
+	Action       string    `json:"action"` // "like", "pass" or "super_like"
 	CreatedAt    time.Time `json:"created_at"`
 }
+
+// IsLike reports whether the swipe likes the target, a super like is a like as well
+func (s Swipe) IsLike() bool {
+	return s.Action == SwipeActionLike || s.Action == SwipeActionSuperLike
+}
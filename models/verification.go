@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	VerificationStatusPending  = "pending"
+	VerificationStatusApproved = "approved"
+	VerificationStatusRejected = "rejected"
+)
+
+const (
+	VerificationDocumentSelfie = "selfie"
+	VerificationDocumentID     = "id_document"
+)
+
+// VerificationReviewerAdmin is the reviewer recorded when an administrator decides a request
+const VerificationReviewerAdmin = "admin"
+
+// VerificationRequest is a user's request to get the verified badge, it waits in the pending
+// queue until an automated checker or an administrator approves or rejects it
+type VerificationRequest struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"user_id"`
+	DocumentType string     `json:"document_type"`
+	ContentType  string     `json:"content_type"`
+	Document     []byte     `json:"-"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason,omitempty"`
+	ReviewedBy   string     `json:"reviewed_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// PendingVerification is a request in the review queue together with the user who sent it, User
+// is nil if the account is gone
+type PendingVerification struct {
+	Request VerificationRequest
+	User    *User
+}
@@ -0,0 +1,96 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// FakeProvider is a local PaymentProvider keeping charges in memory, it never moves money.
+// Webhooks are simulated by posting a WebhookEvent signed with SignPayload.
+type FakeProvider struct {
+	mu           sync.Mutex
+	secret       []byte
+	charges      map[string]*Charge
+	nextChargeID int
+}
+
+// NewFakeProvider creates a FakeProvider verifying webhooks with the given secret,
+// an empty secret makes every signature invalid
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{
+		secret:       []byte(webhookSecret),
+		charges:      make(map[string]*Charge),
+		nextChargeID: 1,
+	}
+}
+
+func (p *FakeProvider) CreateCharge(amount int64, currency string, reference string) (*Charge, error) {
+	if amount < 0 {
+		return nil, errors.New("invalid charge amount")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	charge := &Charge{
+		ID:        "ch_fake_" + strconv.Itoa(p.nextChargeID),
+		Amount:    amount,
+		Currency:  currency,
+		Reference: reference,
+		Status:    ChargeStatusPending,
+	}
+	p.nextChargeID++
+	p.charges[charge.ID] = charge
+
+	chargeCopy := *charge
+	return &chargeCopy, nil
+}
+
+func (p *FakeProvider) CaptureCharge(chargeID string) (*Charge, error) {
+	return p.transition(chargeID, ChargeStatusPending, ChargeStatusCaptured)
+}
+
+func (p *FakeProvider) RefundCharge(chargeID string) (*Charge, error) {
+	return p.transition(chargeID, ChargeStatusCaptured, ChargeStatusRefunded)
+}
+
+func (p *FakeProvider) VerifyWebhookSignature(payload []byte, signature string) error {
+	if len(p.secret) == 0 {
+		return ErrInvalidSignature
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.sign(payload)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SignPayload returns the signature the provider would send for payload
+func (p *FakeProvider) SignPayload(payload []byte) string {
+	return hex.EncodeToString(p.sign(payload))
+}
+
+func (p *FakeProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (p *FakeProvider) transition(chargeID string, from string, to string) (*Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	charge, exists := p.charges[chargeID]
+	if !exists {
+		return nil, errors.New("charge not found")
+	}
+	if charge.Status != from {
+		return nil, errors.New("charge is " + charge.Status + ", expected " + from)
+	}
+	charge.Status = to
+
+	chargeCopy := *charge
+	return &chargeCopy, nil
+}
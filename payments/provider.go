@@ -0,0 +1,42 @@
+package payments
+
+import "errors"
+
+const (
+	ChargeStatusPending  = "pending"
+	ChargeStatusCaptured = "captured"
+	ChargeStatusRefunded = "refunded"
+)
+
+const (
+	WebhookChargeSucceeded = "charge.succeeded"
+	WebhookChargeFailed    = "charge.failed"
+)
+
+// SignatureHeader is the request header carrying the webhook signature
+const SignatureHeader = "X-Payment-Signature"
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Charge is a payment at the provider
+type Charge struct {
+	ID        string `json:"id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+}
+
+// WebhookEvent is the body the provider posts to the payments webhook
+type WebhookEvent struct {
+	Type     string `json:"type"`
+	ChargeID string `json:"charge_id"`
+}
+
+// PaymentProvider abstracts the payment gateway, the service only talks to this interface
+type PaymentProvider interface {
+	CreateCharge(amount int64, currency string, reference string) (*Charge, error)
+	CaptureCharge(chargeID string) (*Charge, error)
+	RefundCharge(chargeID string) (*Charge, error)
+	VerifyWebhookSignature(payload []byte, signature string) error
+}
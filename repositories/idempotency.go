@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type IdempotencyRepository interface {
+	GetRecord(userID int, key string) (*models.IdempotencyRecord, error)
+	SaveRecord(record *models.IdempotencyRecord) error
+}
+
+type idempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+// NewIdempotencyRepository creates a new instance of idempotencyRepository.
+func NewIdempotencyRepository() IdempotencyRepository {
+	return &idempotencyRepository{
+		records: make(map[string]*models.IdempotencyRecord),
+	}
+}
+
+// GetRecord retrieves the unexpired record stored for the user's key.
+func (r *idempotencyRepository) GetRecord(userID int, key string) (*models.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, exists := r.records[idempotencyKey(userID, key)]
+	if !exists || !record.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("idempotency record not found")
+	}
+	recordCopy := *record
+	return &recordCopy, nil
+}
+
+// SaveRecord saves a record, replacing an expired one for the same key.
+func (r *idempotencyRepository) SaveRecord(record *models.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Drop expired records while we hold the lock anyway
+	now := time.Now()
+	for key, existing := range r.records {
+		if !existing.ExpiresAt.After(now) {
+			delete(r.records, key)
+		}
+	}
+
+	key := idempotencyKey(record.UserID, record.Key)
+	if _, exists := r.records[key]; exists {
+		return errors.New("idempotency record already exists")
+	}
+	recordCopy := *record
+	r.records[key] = &recordCopy
+	return nil
+}
+
+func idempotencyKey(userID int, key string) string {
+	return strconv.Itoa(userID) + ":" + key
+}
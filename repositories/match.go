@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type MatchRepository interface {
+	GenerateMatchID() int
+	GetMatchByID(matchID int) (*models.Match, error)
+	GetMatchBetween(userID, otherUserID int) (*models.Match, error)
+	GetMatchesForUser(userID int) []models.Match
+	SaveMatch(match *models.Match) error
+	DeleteMatch(matchID int, unmatchedAt time.Time) error
+	GetUnmatchesForUser(userID int) map[int]time.Time
+}
+
+type matchRepository struct {
+	mu          sync.RWMutex
+	matches     map[int]*models.Match
+	unmatches   map[[2]int]time.Time
+	nextMatchID int
+}
+
+// userPair orders the two users so a pair is the same whoever comes first
+func userPair(userID, otherUserID int) [2]int {
+	return [2]int{min(userID, otherUserID), max(userID, otherUserID)}
+}
+
+// NewMatchRepository creates a new instance of matchRepository.
+func NewMatchRepository() MatchRepository {
+	return &matchRepository{
+		matches:     make(map[int]*models.Match),
+		unmatches:   make(map[[2]int]time.Time),
+		nextMatchID: 1,
+	}
+}
+
+// GenerateMatchID generates the next unique match ID.
+func (r *matchRepository) GenerateMatchID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextMatchID
+	r.nextMatchID++
+	return id
+}
+
+// GetMatchByID retrieves a match by its ID.
+func (r *matchRepository) GetMatchByID(matchID int) (*models.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	match, exists := r.matches[matchID]
+	if !exists {
+		return nil, errors.New("match not found")
+	}
+	matchCopy := *match
+	return &matchCopy, nil
+}
+
+// GetMatchBetween retrieves the match between two users regardless of who liked first.
+func (r *matchRepository) GetMatchBetween(userID, otherUserID int) (*models.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, match := range r.matches {
+		if match.HasUser(userID) && match.HasUser(otherUserID) {
+			matchCopy := *match
+			return &matchCopy, nil
+		}
+	}
+	return nil, errors.New("match not found")
+}
+
+// GetMatchesForUser retrieves all matches the user is part of.
+func (r *matchRepository) GetMatchesForUser(userID int) []models.Match {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []models.Match
+	for _, match := range r.matches {
+		if match.HasUser(userID) {
+			result = append(result, *match)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// SaveMatch saves a new match.
+func (r *matchRepository) SaveMatch(match *models.Match) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.matches[match.ID]; exists {
+		return errors.New("match ID already exists")
+	}
+	for _, existing := range r.matches {
+		if existing.HasUser(match.UserID) && existing.HasUser(match.MatchedID) {
+			return errors.New("match already exists")
+		}
+	}
+	matchCopy := *match
+	r.matches[match.ID] = &matchCopy
+	return nil
+}
+
+// DeleteMatch removes a match and remembers when its users unmatched.
+func (r *matchRepository) DeleteMatch(matchID int, unmatchedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	match, exists := r.matches[matchID]
+	if !exists {
+		return errors.New("match not found")
+	}
+	delete(r.matches, matchID)
+	r.unmatches[userPair(match.UserID, match.MatchedID)] = unmatchedAt
+	return nil
+}
+
+// GetUnmatchesForUser retrieves when the user last unmatched each of their former matches,
+// keyed by the other user.
+func (r *matchRepository) GetUnmatchesForUser(userID int) map[int]time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := map[int]time.Time{}
+	for pair, unmatchedAt := range r.unmatches {
+		switch userID {
+		case pair[0]:
+			result[pair[1]] = unmatchedAt
+		case pair[1]:
+			result[pair[0]] = unmatchedAt
+		}
+	}
+	return result
+}
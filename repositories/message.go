@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type MessageRepository interface {
+	GetConversationByID(conversationID int) (*models.Conversation, error)
+	SaveConversation(conversation *models.Conversation) error
+	GenerateMessageID() int
+	GetMessagesBefore(conversationID int, beforeID int, limit int) []models.Message
+	SaveMessage(message *models.Message) error
+}
+
+type messageRepository struct {
+	mu            sync.RWMutex
+	conversations map[int]*models.Conversation
+	messages      map[int][]models.Message // by conversation, ordered by ID
+	nextMessageID int
+}
+
+// NewMessageRepository creates a new instance of messageRepository.
+func NewMessageRepository() MessageRepository {
+	return &messageRepository{
+		conversations: make(map[int]*models.Conversation),
+		messages:      make(map[int][]models.Message),
+		nextMessageID: 1,
+	}
+}
+
+// GetConversationByID retrieves a conversation by its ID.
+func (r *messageRepository) GetConversationByID(conversationID int) (*models.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	conversation, exists := r.conversations[conversationID]
+	if !exists {
+		return nil, errors.New("conversation not found")
+	}
+	conversationCopy := *conversation
+	return &conversationCopy, nil
+}
+
+// SaveConversation saves a new conversation.
+func (r *messageRepository) SaveConversation(conversation *models.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.conversations[conversation.ID]; exists {
+		return errors.New("conversation already exists")
+	}
+	conversationCopy := *conversation
+	r.conversations[conversation.ID] = &conversationCopy
+	return nil
+}
+
+// GenerateMessageID generates the next unique message ID.
+func (r *messageRepository) GenerateMessageID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextMessageID
+	r.nextMessageID++
+	return id
+}
+
+// GetMessagesBefore retrieves up to limit messages of a conversation with an ID lower than
+// beforeID, newest first. A beforeID of 0 starts at the latest message.
+func (r *messageRepository) GetMessagesBefore(conversationID int, beforeID int, limit int) []models.Message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	messages := r.messages[conversationID]
+	end := len(messages)
+	if beforeID > 0 {
+		end = sort.Search(len(messages), func(i int) bool { return messages[i].ID >= beforeID })
+	}
+
+	result := []models.Message{}
+	for i := end - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, messages[i])
+	}
+	return result
+}
+
+// SaveMessage saves a new message to an existing conversation.
+func (r *messageRepository) SaveMessage(message *models.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.conversations[message.ConversationID]; !exists {
+		return errors.New("conversation not found")
+	}
+	messages := r.messages[message.ConversationID]
+	position := sort.Search(len(messages), func(i int) bool { return messages[i].ID >= message.ID })
+	if position < len(messages) && messages[position].ID == message.ID {
+		return errors.New("message ID already exists")
+	}
+	r.messages[message.ConversationID] = append(messages[:position], append([]models.Message{*message}, messages[position:]...)...)
+	return nil
+}
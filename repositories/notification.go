@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type NotificationRepository interface {
+	GetNotificationsBefore(userID int, beforeID int, limit int) []models.Notification
+	CountUnreadNotifications(userID int) int
+	SaveNotification(notification *models.Notification) error
+	MarkNotificationsRead(userID int, notificationIDs []int) int
+	MarkAllNotificationsRead(userID int) int
+}
+
+type notificationRepository struct {
+	mu                 sync.RWMutex
+	notifications      map[int][]models.Notification // by user, ordered by ID
+	nextNotificationID int
+}
+
+// NewNotificationRepository creates a new instance of notificationRepository.
+func NewNotificationRepository() NotificationRepository {
+	return &notificationRepository{
+		notifications:      make(map[int][]models.Notification),
+		nextNotificationID: 1,
+	}
+}
+
+// GetNotificationsBefore retrieves up to limit notifications of a user with an ID lower than
+// beforeID, newest first. A beforeID of 0 starts at the latest notification.
+func (r *notificationRepository) GetNotificationsBefore(userID int, beforeID int, limit int) []models.Notification {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	notifications := r.notifications[userID]
+	end := len(notifications)
+	if beforeID > 0 {
+		end = sort.Search(len(notifications), func(i int) bool { return notifications[i].ID >= beforeID })
+	}
+
+	result := []models.Notification{}
+	for i := end - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, copyNotification(notifications[i]))
+	}
+	return result
+}
+
+// CountUnreadNotifications counts the notifications of a user that haven't been read.
+func (r *notificationRepository) CountUnreadNotifications(userID int) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	unread := 0
+	for _, notification := range r.notifications[userID] {
+		if !notification.Read {
+			unread++
+		}
+	}
+	return unread
+}
+
+// SaveNotification saves a new notification and assigns its ID.
+func (r *notificationRepository) SaveNotification(notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification.ID = r.nextNotificationID
+	r.nextNotificationID++
+	r.notifications[notification.UserID] = append(r.notifications[notification.UserID], copyNotification(*notification))
+	return nil
+}
+
+// MarkNotificationsRead marks the given notifications of a user as read and returns how many
+// were unread.
+func (r *notificationRepository) MarkNotificationsRead(userID int, notificationIDs []int) int {
+	wanted := map[int]bool{}
+	for _, id := range notificationIDs {
+		wanted[id] = true
+	}
+	return r.markRead(userID, func(notification models.Notification) bool { return wanted[notification.ID] })
+}
+
+// MarkAllNotificationsRead marks every notification of a user as read and returns how many were
+// unread.
+func (r *notificationRepository) MarkAllNotificationsRead(userID int) int {
+	return r.markRead(userID, func(models.Notification) bool { return true })
+}
+
+func (r *notificationRepository) markRead(userID int, selected func(models.Notification) bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	marked := 0
+	notifications := r.notifications[userID]
+	for i := range notifications {
+		if !notifications[i].Read && selected(notifications[i]) {
+			notifications[i].Read = true
+			marked++
+		}
+	}
+	return marked
+}
+
+// copyNotification returns a copy so the stored data can't be mutated outside the lock
+func copyNotification(notification models.Notification) models.Notification {
+	if notification.Data != nil {
+		data := make(map[string]interface{}, len(notification.Data))
+		for key, value := range notification.Data {
+			data[key] = value
+		}
+		notification.Data = data
+	}
+	return notification
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type PlanRepository interface {
+	GetAllPlans() []models.Plan
+	GetPlanByID(planID string) (*models.Plan, error)
+}
+
+// planRepository serves the plan catalogue loaded at startup, it is read-only
+type planRepository struct {
+	plans []models.Plan
+}
+
+// NewPlanRepository creates a new instance of planRepository holding the given catalogue.
+func NewPlanRepository(plans []models.Plan) PlanRepository {
+	return &planRepository{plans: plans}
+}
+
+// GetAllPlans retrieves every plan in catalogue order.
+func (r *planRepository) GetAllPlans() []models.Plan {
+	return append([]models.Plan(nil), r.plans...)
+}
+
+// GetPlanByID retrieves a plan by its ID.
+func (r *planRepository) GetPlanByID(planID string) (*models.Plan, error) {
+	for _, plan := range r.plans {
+		if plan.ID == planID {
+			planCopy := plan
+			return &planCopy, nil
+		}
+	}
+	return nil, errors.New("plan not found")
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"sync"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type PremiumEventRepository interface {
+	SavePremiumEvent(event *models.PremiumEvent) error
+	GetPremiumEventsForUser(userID int) []models.PremiumEvent
+}
+
+type premiumEventRepository struct {
+	mu          sync.RWMutex
+	events      []models.PremiumEvent
+	nextEventID int
+}
+
+// NewPremiumEventRepository creates a new instance of premiumEventRepository.
+func NewPremiumEventRepository() PremiumEventRepository {
+	return &premiumEventRepository{
+		events:      []models.PremiumEvent{},
+		nextEventID: 1,
+	}
+}
+
+// SavePremiumEvent saves a premium event and assigns its ID.
+func (r *premiumEventRepository) SavePremiumEvent(event *models.PremiumEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = r.nextEventID
+	r.nextEventID++
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// GetPremiumEventsForUser retrieves all premium events of a user, oldest first.
+func (r *premiumEventRepository) GetPremiumEventsForUser(userID int) []models.PremiumEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []models.PremiumEvent
+	for _, event := range r.events {
+		if event.UserID == userID {
+			result = append(result, event)
+		}
+	}
+	return result
+}
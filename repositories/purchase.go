@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type PurchaseRepository interface {
+	GeneratePurchaseID() int
+	GetPurchaseByID(purchaseID int) (*models.Purchase, error)
+	GetPurchaseByChargeID(chargeID string) (*models.Purchase, error)
+	SavePurchase(purchase *models.Purchase) error
+	UpdatePurchase(purchase *models.Purchase) error
+}
+
+type purchaseRepository struct {
+	mu             sync.RWMutex
+	purchases      map[int]*models.Purchase
+	nextPurchaseID int
+}
+
+// NewPurchaseRepository creates a new instance of purchaseRepository.
+func NewPurchaseRepository() PurchaseRepository {
+	return &purchaseRepository{
+		purchases:      make(map[int]*models.Purchase),
+		nextPurchaseID: 1,
+	}
+}
+
+// GeneratePurchaseID generates the next unique purchase ID.
+func (r *purchaseRepository) GeneratePurchaseID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextPurchaseID
+	r.nextPurchaseID++
+	return id
+}
+
+// GetPurchaseByID retrieves a purchase by its ID.
+func (r *purchaseRepository) GetPurchaseByID(purchaseID int) (*models.Purchase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	purchase, exists := r.purchases[purchaseID]
+	if !exists {
+		return nil, errors.New("purchase not found")
+	}
+	purchaseCopy := *purchase
+	return &purchaseCopy, nil
+}
+
+// GetPurchaseByChargeID retrieves a purchase by the provider's charge ID.
+func (r *purchaseRepository) GetPurchaseByChargeID(chargeID string) (*models.Purchase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, purchase := range r.purchases {
+		if purchase.ChargeID == chargeID {
+			purchaseCopy := *purchase
+			return &purchaseCopy, nil
+		}
+	}
+	return nil, errors.New("purchase not found")
+}
+
+// SavePurchase saves a new purchase.
+func (r *purchaseRepository) SavePurchase(purchase *models.Purchase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.purchases[purchase.ID]; exists {
+		return errors.New("purchase ID already exists")
+	}
+	purchaseCopy := *purchase
+	r.purchases[purchase.ID] = &purchaseCopy
+	return nil
+}
+
+// UpdatePurchase updates an existing purchase.
+func (r *purchaseRepository) UpdatePurchase(purchase *models.Purchase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.purchases[purchase.ID]; !exists {
+		return errors.New("purchase not found")
+	}
+	purchaseCopy := *purchase
+	r.purchases[purchase.ID] = &purchaseCopy
+	return nil
+}
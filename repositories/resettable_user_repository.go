@@ -21,8 +21,7 @@ func NewResettableUserRepository() *ResettableUserRepository {
 
 // ClearData resets all users in the repository.
 func (r *ResettableUserRepository) ClearData() {
-	r.users = make(map[int]*models.User)
-	r.nextUserID = 1
+	r.userRepository.ClearData()
 }
 
 // SeedTestData adds initial users to the repository for testing.
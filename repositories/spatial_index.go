@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"math"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// The spatial index splits the globe into a grid of gridCellDegrees sized cells so that a
+// radius search only has to look at the users in the cells overlapping the search area.
+const (
+	gridCellDegrees = 0.2
+	gridLatCells    = int(180 / gridCellDegrees)
+	gridLngCells    = int(360 / gridCellDegrees)
+	kmPerDegree     = 111.32
+)
+
+type gridCell struct {
+	lat int
+	lng int
+}
+
+// cellRange is the block of cells covering a search area, lngs may wrap around the antimeridian
+type cellRange struct {
+	minLat int
+	maxLat int
+	lngs   []int
+}
+
+func cellFor(location models.Location) gridCell {
+	return gridCell{
+		lat: clampCell(int(math.Floor((location.Latitude+90)/gridCellDegrees)), gridLatCells),
+		lng: wrapCell(int(math.Floor((location.Longitude+180)/gridCellDegrees)), gridLngCells),
+	}
+}
+
+// cellsWithin returns the cells overlapping the bounding box of the circle around center
+func cellsWithin(center models.Location, radiusKm float64) cellRange {
+	latDelta := radiusKm / kmPerDegree
+	minLat := math.Max(-90, center.Latitude-latDelta)
+	maxLat := math.Min(90, center.Latitude+latDelta)
+
+	cells := cellRange{
+		minLat: cellFor(models.Location{Latitude: minLat}).lat,
+		maxLat: cellFor(models.Location{Latitude: maxLat}).lat,
+	}
+
+	// Longitude degrees shrink towards the poles, size the box for the most poleward edge
+	poleward := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	lngDelta := 180.0
+	if cos := math.Cos(poleward * math.Pi / 180); cos > 1e-6 {
+		lngDelta = math.Min(180, radiusKm/(kmPerDegree*cos))
+	}
+
+	first := int(math.Floor((center.Longitude - lngDelta + 180) / gridCellDegrees))
+	last := int(math.Floor((center.Longitude + lngDelta + 180) / gridCellDegrees))
+	if last-first+1 >= gridLngCells {
+		first, last = 0, gridLngCells-1
+	}
+	for lng := first; lng <= last; lng++ {
+		cells.lngs = append(cells.lngs, wrapCell(lng, gridLngCells))
+	}
+	return cells
+}
+
+func clampCell(index, cells int) int {
+	if index < 0 {
+		return 0
+	}
+	if index >= cells {
+		return cells - 1
+	}
+	return index
+}
+
+func wrapCell(index, cells int) int {
+	return ((index % cells) + cells) % cells
+}
+
+// gridIndex maps grid cells to the IDs of the users located in them
+type gridIndex struct {
+	cells     map[gridCell]map[int]bool
+	userCells map[int]gridCell
+}
+
+func newGridIndex() *gridIndex {
+	return &gridIndex{
+		cells:     make(map[gridCell]map[int]bool),
+		userCells: make(map[int]gridCell),
+	}
+}
+
+// set moves the user to the cell of location, a nil location removes the user from the index
+func (g *gridIndex) set(userID int, location *models.Location) {
+	if cell, exists := g.userCells[userID]; exists {
+		delete(g.cells[cell], userID)
+		if len(g.cells[cell]) == 0 {
+			delete(g.cells, cell)
+		}
+		delete(g.userCells, userID)
+	}
+	if location == nil {
+		return
+	}
+
+	cell := cellFor(*location)
+	if g.cells[cell] == nil {
+		g.cells[cell] = make(map[int]bool)
+	}
+	g.cells[cell][userID] = true
+	g.userCells[userID] = cell
+}
+
+// query returns the IDs of the users in the cells around center, callers still have to
+// check the exact distance since cells stick out of the search circle
+func (g *gridIndex) query(center models.Location, radiusKm float64) []int {
+	cells := cellsWithin(center, radiusKm)
+	var ids []int
+	for lat := cells.minLat; lat <= cells.maxLat; lat++ {
+		for _, lng := range cells.lngs {
+			for id := range g.cells[gridCell{lat: lat, lng: lng}] {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
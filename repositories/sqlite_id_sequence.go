@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// sqliteIDSequence hands out the IDs of a table whose rows get their ID before they are
+// inserted, it continues after the highest ID stored when it is first used
+type sqliteIDSequence struct {
+	db    *sql.DB
+	table string
+
+	mu     sync.Mutex
+	seeded bool
+	nextID int
+}
+
+func newSQLiteIDSequence(db *sql.DB, table string) *sqliteIDSequence {
+	return &sqliteIDSequence{db: db, table: table, nextID: 1}
+}
+
+func (s *sqliteIDSequence) next() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.seeded {
+		// On failure the sequence is seeded again on the next call, the insert of this ID will fail
+		// against the same database anyway
+		var maxID int
+		if err := s.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM ` + s.table).Scan(&maxID); err == nil {
+			s.seeded = true
+			s.nextID = maxID + 1
+		}
+	}
+	id := s.nextID
+	s.nextID++
+	return id
+}
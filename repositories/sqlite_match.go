@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+const matchColumns = `id, user_id, matched_id, created_at`
+
+type sqliteMatchRepository struct {
+	db  *sql.DB
+	ids *sqliteIDSequence
+}
+
+// NewMatchRepositoryFor keeps matches next to the users: in the same database when userRepo is
+// backed by SQLite, whose migrations created the table, and in memory otherwise.
+func NewMatchRepositoryFor(userRepo UserRepository) MatchRepository {
+	if sqliteUsers, ok := userRepo.(*sqliteUserRepository); ok {
+		return &sqliteMatchRepository{db: sqliteUsers.db, ids: newSQLiteIDSequence(sqliteUsers.db, "matches")}
+	}
+	return NewMatchRepository()
+}
+
+// GenerateMatchID generates the next unique match ID.
+func (r *sqliteMatchRepository) GenerateMatchID() int {
+	return r.ids.next()
+}
+
+// GetMatchByID retrieves a match by its ID.
+func (r *sqliteMatchRepository) GetMatchByID(matchID int) (*models.Match, error) {
+	return r.queryMatch(`SELECT `+matchColumns+` FROM matches WHERE id = ?`, matchID)
+}
+
+// GetMatchBetween retrieves the match between two users regardless of who liked first.
+func (r *sqliteMatchRepository) GetMatchBetween(userID, otherUserID int) (*models.Match, error) {
+	return r.queryMatch(`SELECT `+matchColumns+` FROM matches
+		WHERE (user_id = ? AND matched_id = ?) OR (user_id = ? AND matched_id = ?)`,
+		userID, otherUserID, otherUserID, userID)
+}
+
+// GetMatchesForUser retrieves all matches the user is part of.
+func (r *sqliteMatchRepository) GetMatchesForUser(userID int) []models.Match {
+	rows, err := r.db.Query(`SELECT `+matchColumns+` FROM matches WHERE user_id = ? OR matched_id = ? ORDER BY id`, userID, userID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var result []models.Match
+	for rows.Next() {
+		var match models.Match
+		if err := rows.Scan(&match.ID, &match.UserID, &match.MatchedID, &match.CreatedAt); err != nil {
+			return nil
+		}
+		result = append(result, match)
+	}
+	return result
+}
+
+// SaveMatch saves a new match, the unique index on the pair rejects a second match between the
+// same users.
+func (r *sqliteMatchRepository) SaveMatch(match *models.Match) error {
+	_, err := r.db.Exec(`INSERT INTO matches (`+matchColumns+`) VALUES (?, ?, ?, ?)`,
+		match.ID, match.UserID, match.MatchedID, match.CreatedAt.UTC())
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		if strings.Contains(err.Error(), "matches.id") {
+			return errors.New("match ID already exists")
+		}
+		return errors.New("match already exists")
+	}
+	return err
+}
+
+// DeleteMatch removes a match and remembers when its users unmatched.
+func (r *sqliteMatchRepository) DeleteMatch(matchID int, unmatchedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID, matchedID int
+	err = tx.QueryRow(`DELETE FROM matches WHERE id = ? RETURNING user_id, matched_id`, matchID).Scan(&userID, &matchedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("match not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	pair := userPair(userID, matchedID)
+	if _, err := tx.Exec(`INSERT INTO unmatches (user_id, other_id, unmatched_at) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, other_id) DO UPDATE SET unmatched_at = excluded.unmatched_at`,
+		pair[0], pair[1], unmatchedAt.UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetUnmatchesForUser retrieves when the user last unmatched each of their former matches,
+// keyed by the other user.
+func (r *sqliteMatchRepository) GetUnmatchesForUser(userID int) map[int]time.Time {
+	result := map[int]time.Time{}
+	rows, err := r.db.Query(`SELECT other_id, unmatched_at FROM unmatches WHERE user_id = ?
+		UNION ALL SELECT user_id, unmatched_at FROM unmatches WHERE other_id = ?`, userID, userID)
+	if err != nil {
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var otherID int
+		var unmatchedAt time.Time
+		if err := rows.Scan(&otherID, &unmatchedAt); err != nil {
+			return map[int]time.Time{}
+		}
+		result[otherID] = unmatchedAt
+	}
+	return result
+}
+
+func (r *sqliteMatchRepository) queryMatch(query string, args ...interface{}) (*models.Match, error) {
+	var match models.Match
+	err := r.db.QueryRow(query, args...).Scan(&match.ID, &match.UserID, &match.MatchedID, &match.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("match not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
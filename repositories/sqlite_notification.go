@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type sqliteNotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepositoryFor keeps notifications next to the users: in the same database when
+// userRepo is backed by SQLite, whose migrations created the table, and in memory otherwise.
+func NewNotificationRepositoryFor(userRepo UserRepository) NotificationRepository {
+	if sqliteUsers, ok := userRepo.(*sqliteUserRepository); ok {
+		return &sqliteNotificationRepository{db: sqliteUsers.db}
+	}
+	return NewNotificationRepository()
+}
+
+// GetNotificationsBefore retrieves up to limit notifications of a user with an ID lower than
+// beforeID, newest first. A beforeID of 0 starts at the latest notification.
+func (r *sqliteNotificationRepository) GetNotificationsBefore(userID int, beforeID int, limit int) []models.Notification {
+	query := `SELECT id, user_id, type, data, is_read, created_at FROM notifications WHERE user_id = ?`
+	args := []interface{}{userID}
+	if beforeID > 0 {
+		query += ` AND id < ?`
+		args = append(args, beforeID)
+	}
+	rows, err := r.db.Query(query+` ORDER BY id DESC LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return []models.Notification{}
+	}
+	defer rows.Close()
+
+	result := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		var data string
+		if err := rows.Scan(&notification.ID, &notification.UserID, &notification.Type, &data, &notification.Read, &notification.CreatedAt); err != nil {
+			return []models.Notification{}
+		}
+		if err := json.Unmarshal([]byte(data), &notification.Data); err != nil {
+			return []models.Notification{}
+		}
+		result = append(result, notification)
+	}
+	return result
+}
+
+// CountUnreadNotifications counts the notifications of a user that haven't been read.
+func (r *sqliteNotificationRepository) CountUnreadNotifications(userID int) int {
+	var unread int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = ? AND is_read = 0`, userID).Scan(&unread); err != nil {
+		return 0
+	}
+	return unread
+}
+
+// SaveNotification saves a new notification and assigns its ID.
+func (r *sqliteNotificationRepository) SaveNotification(notification *models.Notification) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.Exec(`INSERT INTO notifications (user_id, type, data, is_read, created_at) VALUES (?, ?, ?, ?, ?)`,
+		notification.UserID, notification.Type, string(data), notification.Read, notification.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	notification.ID = int(id)
+	return nil
+}
+
+// MarkNotificationsRead marks the given notifications of a user as read and returns how many
+// were unread.
+func (r *sqliteNotificationRepository) MarkNotificationsRead(userID int, notificationIDs []int) int {
+	if len(notificationIDs) == 0 {
+		return 0
+	}
+	args := []interface{}{userID}
+	for _, id := range notificationIDs {
+		args = append(args, id)
+	}
+	return r.markRead(`user_id = ? AND id IN (?`+strings.Repeat(", ?", len(notificationIDs)-1)+`)`, args...)
+}
+
+// MarkAllNotificationsRead marks every notification of a user as read and returns how many were
+// unread.
+func (r *sqliteNotificationRepository) MarkAllNotificationsRead(userID int) int {
+	return r.markRead(`user_id = ?`, userID)
+}
+
+func (r *sqliteNotificationRepository) markRead(condition string, args ...interface{}) int {
+	result, err := r.db.Exec(`UPDATE notifications SET is_read = 1 WHERE is_read = 0 AND `+condition, args...)
+	if err != nil {
+		return 0
+	}
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return int(marked)
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+const purchaseColumns = `id, user_id, plan_id, charge_id, amount, currency, status, failure_reason, created_at, updated_at`
+
+type sqlitePurchaseRepository struct {
+	db  *sql.DB
+	ids *sqliteIDSequence
+}
+
+// NewPurchaseRepositoryFor keeps purchases next to the users: in the same database when userRepo
+// is backed by SQLite, whose migrations created the table, and in memory otherwise.
+func NewPurchaseRepositoryFor(userRepo UserRepository) PurchaseRepository {
+	if sqliteUsers, ok := userRepo.(*sqliteUserRepository); ok {
+		return &sqlitePurchaseRepository{db: sqliteUsers.db, ids: newSQLiteIDSequence(sqliteUsers.db, "purchases")}
+	}
+	return NewPurchaseRepository()
+}
+
+// GeneratePurchaseID generates the next unique purchase ID.
+func (r *sqlitePurchaseRepository) GeneratePurchaseID() int {
+	return r.ids.next()
+}
+
+// GetPurchaseByID retrieves a purchase by its ID.
+func (r *sqlitePurchaseRepository) GetPurchaseByID(purchaseID int) (*models.Purchase, error) {
+	return r.queryPurchase(`SELECT `+purchaseColumns+` FROM purchases WHERE id = ?`, purchaseID)
+}
+
+// GetPurchaseByChargeID retrieves a purchase by the provider's charge ID.
+func (r *sqlitePurchaseRepository) GetPurchaseByChargeID(chargeID string) (*models.Purchase, error) {
+	return r.queryPurchase(`SELECT `+purchaseColumns+` FROM purchases WHERE charge_id = ?`, chargeID)
+}
+
+// SavePurchase saves a new purchase.
+func (r *sqlitePurchaseRepository) SavePurchase(purchase *models.Purchase) error {
+	_, err := r.db.Exec(`INSERT INTO purchases (`+purchaseColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		purchase.ID, purchase.UserID, purchase.PlanID, purchase.ChargeID, purchase.Amount, purchase.Currency,
+		purchase.Status, purchase.FailureReason, purchase.CreatedAt.UTC(), purchase.UpdatedAt.UTC())
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed: purchases.id") {
+		return errors.New("purchase ID already exists")
+	}
+	return err
+}
+
+// UpdatePurchase updates an existing purchase.
+func (r *sqlitePurchaseRepository) UpdatePurchase(purchase *models.Purchase) error {
+	result, err := r.db.Exec(`UPDATE purchases SET user_id = ?, plan_id = ?, charge_id = ?, amount = ?, currency = ?,
+		status = ?, failure_reason = ?, created_at = ?, updated_at = ? WHERE id = ?`,
+		purchase.UserID, purchase.PlanID, purchase.ChargeID, purchase.Amount, purchase.Currency, purchase.Status,
+		purchase.FailureReason, purchase.CreatedAt.UTC(), purchase.UpdatedAt.UTC(), purchase.ID)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errors.New("purchase not found")
+	}
+	return nil
+}
+
+func (r *sqlitePurchaseRepository) queryPurchase(query string, args ...interface{}) (*models.Purchase, error) {
+	var purchase models.Purchase
+	err := r.db.QueryRow(query, args...).Scan(&purchase.ID, &purchase.UserID, &purchase.PlanID, &purchase.ChargeID,
+		&purchase.Amount, &purchase.Currency, &purchase.Status, &purchase.FailureReason, &purchase.CreatedAt, &purchase.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("purchase not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
@@ -0,0 +1,434 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/migrations"
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	_ "modernc.org/sqlite"
+)
+
+// userColumnList is the column order used by scanUser and userArgs, keep the three in sync
+var userColumnList = []string{
+	"id", "email", "password", "phone", "name", "gender", "birthdate", "bio", "occupation", "interests",
+	"interested_in", "min_age", "max_age", "max_distance_km", "latitude", "longitude", "is_inactive",
+	"is_verified", "boosted_until", "premium_expiry", "unlimited_swipes", "profile_boost", "rewind",
+	"unlimited_swipes_until", "profile_boost_until", "rewind_until", "created_at", "updated_at",
+}
+
+// userWriteColumnList adds the spatial index columns, they are derived from the location and never read back
+var userWriteColumnList = append(userColumnList[:len(userColumnList):len(userColumnList)], "cell_lat", "cell_lng")
+
+var (
+	userColumns   = strings.Join(userColumnList, ", ")
+	insertUserSQL = `INSERT INTO users (` + strings.Join(userWriteColumnList, ", ") + `) VALUES (?` +
+		strings.Repeat(", ?", len(userWriteColumnList)-1) + `)`
+	updateUserSQL = `UPDATE users SET ` + strings.Join(userWriteColumnList[1:], " = ?, ") + ` = ? WHERE id = ?`
+)
+
+// activityBatchSize is how many users GetSwipeActivity binds to one query, SQLite refuses
+// statements with more than 32766 parameters
+const activityBatchSize = 500
+
+type sqliteUserRepository struct {
+	db *sql.DB
+
+	// idMu guards nextUserID, IDs are handed out before the row is inserted
+	idMu       sync.Mutex
+	nextUserID int
+}
+
+// NewSQLiteUserRepository opens (or creates) the SQLite file at path and returns a UserRepository backed by it.
+func NewSQLiteUserRepository(path string) (UserRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only allows a single writer, serialise access through one connection
+	db.SetMaxOpenConns(1)
+
+	// Bring the schema up to date before serving anything
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	var maxID int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM users`).Scan(&maxID); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqliteUserRepository{db: db, nextUserID: maxID + 1}, nil
+}
+
+// GenerateUserID generates the next unique user ID.
+func (r *sqliteUserRepository) GenerateUserID() int {
+	r.idMu.Lock()
+	defer r.idMu.Unlock()
+	id := r.nextUserID
+	r.nextUserID++
+	return id
+}
+
+// GetAllUsers retrieves all users.
+func (r *sqliteUserRepository) GetAllUsers() []*models.User {
+	return r.queryUsers(`SELECT ` + userColumns + ` FROM users ORDER BY id`)
+}
+
+// GetUsersAfter retrieves up to limit users with an ID greater than afterID, ordered by ID.
+func (r *sqliteUserRepository) GetUsersAfter(afterID int, limit int) []*models.User {
+	return r.queryUsers(`SELECT `+userColumns+` FROM users WHERE id > ? ORDER BY id LIMIT ?`, afterID, limit)
+}
+
+// GetUsersNear retrieves the users located within radiusKm of center.
+func (r *sqliteUserRepository) GetUsersNear(center models.Location, radiusKm float64) []*models.User {
+	cells := cellsWithin(center, radiusKm)
+	args := []interface{}{cells.minLat, cells.maxLat}
+	for _, lng := range cells.lngs {
+		args = append(args, lng)
+	}
+
+	users := r.queryUsers(`SELECT `+userColumns+` FROM users WHERE cell_lat BETWEEN ? AND ? AND cell_lng IN (?`+
+		strings.Repeat(", ?", len(cells.lngs)-1)+`) ORDER BY id`, args...)
+
+	// Cells stick out of the search circle, check the exact distance
+	var result []*models.User
+	for _, user := range users {
+		if center.DistanceKm(*user.Location) <= radiusKm {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+// GetBoostedUsers retrieves the users whose boost window is open at now, ordered by ID.
+func (r *sqliteUserRepository) GetBoostedUsers(now time.Time) []*models.User {
+	// Times are stored as RFC 3339 text, which only sorts correctly down to whole seconds. Let
+	// the index narrow it down with a second of slack and check the exact time here.
+	var result []*models.User
+	for _, user := range r.queryUsers(`SELECT `+userColumns+` FROM users WHERE boosted_until > ? ORDER BY id`,
+		now.UTC().Truncate(time.Second).Add(-time.Second)) {
+		if user.IsBoosted(now) {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+// GetUserByID retrieves a user by their ID.
+func (r *sqliteUserRepository) GetUserByID(userID int) (*models.User, error) {
+	return r.getUserWhere(`id = ?`, userID)
+}
+
+// GetUserByEmail retrieves a user by their email.
+func (r *sqliteUserRepository) GetUserByEmail(email string) (*models.User, error) {
+	return r.getUserWhere(`email = ?`, email)
+}
+
+// GetUserByPhone retrieves a user by their phone number.
+func (r *sqliteUserRepository) GetUserByPhone(phone string) (*models.User, error) {
+	return r.getUserWhere(`phone = ?`, phone)
+}
+
+// SaveUser saves a new user.
+func (r *sqliteUserRepository) SaveUser(user *models.User) error {
+	if _, err := r.GetUserByID(user.ID); err == nil {
+		return errors.New("user ID already exists")
+	}
+
+	args, err := userArgs(user)
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(insertUserSQL, args...); err != nil {
+		return uniqueConstraintError(err)
+	}
+
+	// Keep generated IDs ahead of explicitly provided ones
+	r.idMu.Lock()
+	if user.ID >= r.nextUserID {
+		r.nextUserID = user.ID + 1
+	}
+	r.idMu.Unlock()
+	return nil
+}
+
+// UpdateUser updates an existing user.
+func (r *sqliteUserRepository) UpdateUser(user *models.User) error {
+	args, err := userArgs(user)
+	if err != nil {
+		return err
+	}
+	// The ID moves from the first column to the WHERE clause, the cell columns stay at the end
+	result, err := r.db.Exec(updateUserSQL, append(args[1:], user.ID)...)
+	if err != nil {
+		return uniqueConstraintError(err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// GetSwipesForUser retrieves all swipes for a specific user.
+func (r *sqliteUserRepository) GetSwipesForUser(userID int) []models.Swipe {
+	return r.querySwipes(`SELECT id, user_id, target_user_id, action, created_at FROM swipes WHERE user_id = ? ORDER BY id`, userID)
+}
+
+// GetSwipesReceived retrieves all swipes made on a specific user, oldest first.
+func (r *sqliteUserRepository) GetSwipesReceived(targetUserID int) []models.Swipe {
+	return r.querySwipes(`SELECT id, user_id, target_user_id, action, created_at FROM swipes WHERE target_user_id = ? ORDER BY id`, targetUserID)
+}
+
+func (r *sqliteUserRepository) querySwipes(query string, args ...interface{}) []models.Swipe {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var result []models.Swipe
+	for rows.Next() {
+		var swipe models.Swipe
+		if err := rows.Scan(&swipe.ID, &swipe.UserID, &swipe.TargetUserID, &swipe.Action, &swipe.CreatedAt); err != nil {
+			return nil
+		}
+		result = append(result, swipe)
+	}
+	return result
+}
+
+// GetSwipeActivity summarises the swipes made by and on each of the given users up to asOf. The
+// users are looked up activityBatchSize at a time to stay below SQLite's limit on bound parameters.
+func (r *sqliteUserRepository) GetSwipeActivity(userIDs []int, asOf time.Time) (map[int]models.SwipeActivity, error) {
+	result := map[int]models.SwipeActivity{}
+	for start := 0; start < len(userIDs); start += activityBatchSize {
+		end := start + activityBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		if err := r.addSwipeActivity(result, userIDs[start:end], asOf); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// addSwipeActivity adds the activity of a batch of users to result
+func (r *sqliteUserRepository) addSwipeActivity(result map[int]models.SwipeActivity, userIDs []int, asOf time.Time) error {
+	placeholders := `(?` + strings.Repeat(", ?", len(userIDs)-1) + `)`
+	args := make([]interface{}, 0, len(userIDs)+1)
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	args = append(args, asOf.UTC())
+
+	rows, err := r.db.Query(`SELECT target_user_id, SUM(CASE WHEN action IN ('like', 'super_like') THEN 1 ELSE 0 END), COUNT(*)
+		FROM swipes WHERE target_user_id IN `+placeholders+` AND created_at <= ? GROUP BY target_user_id`, args...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var userID int
+		var activity models.SwipeActivity
+		if err := rows.Scan(&userID, &activity.LikesReceived, &activity.SwipesReceived); err != nil {
+			rows.Close()
+			return err
+		}
+		result[userID] = activity
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// The newest swipe has the highest ID, selecting the row keeps created_at's DATETIME type for scanning
+	rows, err = r.db.Query(`SELECT user_id, created_at FROM swipes WHERE id IN
+		(SELECT MAX(id) FROM swipes WHERE user_id IN `+placeholders+` AND created_at <= ? GROUP BY user_id)`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int
+		var lastSwipeAt time.Time
+		if err := rows.Scan(&userID, &lastSwipeAt); err != nil {
+			return err
+		}
+		activity := result[userID]
+		activity.LastSwipeAt = lastSwipeAt
+		result[userID] = activity
+	}
+	return rows.Err()
+}
+
+// SaveSwipe saves a swipe action and assigns its ID.
+func (r *sqliteUserRepository) SaveSwipe(swipe *models.Swipe) error {
+	result, err := r.db.Exec(`INSERT INTO swipes (user_id, target_user_id, action, created_at) VALUES (?, ?, ?, ?)`,
+		swipe.UserID, swipe.TargetUserID, swipe.Action, swipe.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	swipe.ID = int(id)
+	return nil
+}
+
+// DeleteSwipe removes a swipe.
+func (r *sqliteUserRepository) DeleteSwipe(swipeID int) error {
+	result, err := r.db.Exec(`DELETE FROM swipes WHERE id = ?`, swipeID)
+	if err != nil {
+		return err
+	}
+	if deleted, err := result.RowsAffected(); err != nil || deleted == 0 {
+		return errors.New("swipe not found")
+	}
+	return nil
+}
+
+// Close releases the underlying database handle.
+func (r *sqliteUserRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *sqliteUserRepository) queryUsers(query string, args ...interface{}) []*models.User {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var result []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil
+		}
+		result = append(result, user)
+	}
+	return result
+}
+
+func (r *sqliteUserRepository) getUserWhere(condition string, arg interface{}) (*models.User, error) {
+	row := r.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE `+condition, arg)
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("user not found")
+	}
+	return user, err
+}
+
+// uniqueConstraintError maps SQLite unique violations to the errors the in-memory repository returns
+func uniqueConstraintError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "UNIQUE constraint failed: users.email"):
+		return ErrEmailAlreadyExists
+	case strings.Contains(err.Error(), "UNIQUE constraint failed: users.phone"):
+		return ErrPhoneAlreadyExists
+	}
+	return err
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func userArgs(user *models.User) ([]interface{}, error) {
+	interests, err := json.Marshal(nonNilStrings(user.Interests))
+	if err != nil {
+		return nil, err
+	}
+	interestedIn, err := json.Marshal(nonNilStrings(user.Preferences.InterestedIn))
+	if err != nil {
+		return nil, err
+	}
+	var latitude, longitude, cellLat, cellLng interface{}
+	if user.Location != nil {
+		cell := cellFor(*user.Location)
+		latitude, longitude, cellLat, cellLng = user.Location.Latitude, user.Location.Longitude, cell.lat, cell.lng
+	}
+	return []interface{}{
+		user.ID, user.Email, user.Password, user.Phone, user.Name, user.Gender, user.Birthdate.String(), user.Bio,
+		user.Occupation, string(interests), string(interestedIn), user.Preferences.MinAge, user.Preferences.MaxAge,
+		user.Preferences.MaxDistanceKm, latitude, longitude, user.IsInactive, user.IsVerified, utcTime(user.BoostedUntil),
+		user.PremiumExpiry, user.PremiumFeatures.UnlimitedSwipes, user.PremiumFeatures.ProfileBoost,
+		user.PremiumFeatures.Rewind, utcTime(user.PremiumFeatureExpiry.UnlimitedSwipes),
+		utcTime(user.PremiumFeatureExpiry.ProfileBoost), utcTime(user.PremiumFeatureExpiry.Rewind),
+		user.CreatedAt, user.UpdatedAt, cellLat, cellLng,
+	}, nil
+}
+
+// utcTime stores times in UTC so the text values SQLite compares sort chronologically
+func utcTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// nullTimePtr turns a nullable column into a pointer, nil for NULL
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var boostedUntil, premiumExpiry, unlimitedSwipesUntil, profileBoostUntil, rewindUntil sql.NullTime
+	var birthdate, interests, interestedIn string
+	var latitude, longitude sql.NullFloat64
+	err := row.Scan(&user.ID, &user.Email, &user.Password, &user.Phone, &user.Name, &user.Gender, &birthdate,
+		&user.Bio, &user.Occupation, &interests, &interestedIn, &user.Preferences.MinAge, &user.Preferences.MaxAge,
+		&user.Preferences.MaxDistanceKm, &latitude, &longitude, &user.IsInactive, &user.IsVerified, &boostedUntil,
+		&premiumExpiry, &user.PremiumFeatures.UnlimitedSwipes, &user.PremiumFeatures.ProfileBoost,
+		&user.PremiumFeatures.Rewind, &unlimitedSwipesUntil, &profileBoostUntil, &rewindUntil, &user.CreatedAt,
+		&user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if user.Birthdate, err = models.ParseDate(birthdate); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(interests), &user.Interests); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(interestedIn), &user.Preferences.InterestedIn); err != nil {
+		return nil, err
+	}
+	if latitude.Valid && longitude.Valid {
+		user.Location = &models.Location{Latitude: latitude.Float64, Longitude: longitude.Float64}
+	}
+	if boostedUntil.Valid {
+		until := boostedUntil.Time
+		user.BoostedUntil = &until
+	}
+	if premiumExpiry.Valid {
+		expiry := premiumExpiry.Time
+		user.PremiumExpiry = &expiry
+	}
+	user.PremiumFeatureExpiry = models.PremiumFeatureExpiry{
+		UnlimitedSwipes: nullTimePtr(unlimitedSwipesUntil),
+		ProfileBoost:    nullTimePtr(profileBoostUntil),
+		Rewind:          nullTimePtr(rewindUntil),
+	}
+	return &user, nil
+}
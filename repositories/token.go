@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type TokenRepository interface {
+	SaveRefreshToken(token *models.RefreshToken) error
+	GetRefreshToken(tokenHash string) (*models.RefreshToken, error)
+	UpdateRefreshToken(token *models.RefreshToken) error
+	RevokeRefreshToken(tokenHash string, now time.Time) (*models.RefreshToken, error)
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) bool
+}
+
+type tokenRepository struct {
+	mu            sync.RWMutex
+	refreshTokens map[string]*models.RefreshToken
+	// revokedAccess maps a revoked jti to the expiry of its token, after which it can be forgotten
+	revokedAccess map[string]time.Time
+}
+
+// NewTokenRepository creates a new instance of tokenRepository.
+func NewTokenRepository() TokenRepository {
+	return &tokenRepository{
+		refreshTokens: make(map[string]*models.RefreshToken),
+		revokedAccess: make(map[string]time.Time),
+	}
+}
+
+// SaveRefreshToken saves a new refresh token.
+func (r *tokenRepository) SaveRefreshToken(token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.refreshTokens[token.TokenHash]; exists {
+		return errors.New("refresh token already exists")
+	}
+	tokenCopy := *token
+	r.refreshTokens[token.TokenHash] = &tokenCopy
+	return nil
+}
+
+// GetRefreshToken retrieves a refresh token by its hash.
+func (r *tokenRepository) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	token, exists := r.refreshTokens[tokenHash]
+	if !exists {
+		return nil, errors.New("refresh token not found")
+	}
+	tokenCopy := *token
+	return &tokenCopy, nil
+}
+
+// UpdateRefreshToken updates an existing refresh token.
+func (r *tokenRepository) UpdateRefreshToken(token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.refreshTokens[token.TokenHash]; !exists {
+		return errors.New("refresh token not found")
+	}
+	tokenCopy := *token
+	r.refreshTokens[token.TokenHash] = &tokenCopy
+	return nil
+}
+
+// RevokeRefreshToken revokes a refresh token that is still valid at now and returns it. The
+// check and the revoke happen under one lock, so a token can only ever be used once.
+func (r *tokenRepository) RevokeRefreshToken(tokenHash string, now time.Time) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, exists := r.refreshTokens[tokenHash]
+	if !exists {
+		return nil, errors.New("refresh token not found")
+	}
+	if token.RevokedAt != nil {
+		return nil, errors.New("refresh token already revoked")
+	}
+	if !token.ExpiresAt.After(now) {
+		return nil, errors.New("refresh token expired")
+	}
+	token.RevokedAt = &now
+	tokenCopy := *token
+	return &tokenCopy, nil
+}
+
+// RevokeAccessToken marks an access token as revoked until it expires.
+func (r *tokenRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Drop entries of tokens that have expired anyway
+	now := time.Now()
+	for revokedJTI, expiry := range r.revokedAccess {
+		if expiry.Before(now) {
+			delete(r.revokedAccess, revokedJTI)
+		}
+	}
+
+	r.revokedAccess[jti] = expiresAt
+	return nil
+}
+
+// IsAccessTokenRevoked checks whether the access token with the given jti has been revoked.
+func (r *tokenRepository) IsAccessTokenRevoked(jti string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, revoked := r.revokedAccess[jti]
+	return revoked
+}
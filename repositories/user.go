@@ -2,39 +2,64 @@ package repositories
 
 import (
 	"errors"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
 )
 
+// Returned when a write would give two users the same email or phone number
+var (
+	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrPhoneAlreadyExists = errors.New("phone number already exists")
+)
+
 type UserRepository interface {
 	GenerateUserID() int
 	GetAllUsers() []*models.User
+	GetUsersAfter(afterID int, limit int) []*models.User
+	GetUsersNear(center models.Location, radiusKm float64) []*models.User
+	GetBoostedUsers(now time.Time) []*models.User
 	GetUserByID(userID int) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByPhone(phone string) (*models.User, error)
 	SaveUser(user *models.User) error
 	UpdateUser(user *models.User) error
 	GetSwipesForUser(userID int) []models.Swipe
+	GetSwipesReceived(targetUserID int) []models.Swipe
+	GetSwipeActivity(userIDs []int, asOf time.Time) (map[int]models.SwipeActivity, error)
 	SaveSwipe(swipe *models.Swipe) error
+	DeleteSwipe(swipeID int) error
 }
 
+// userRepository keeps users in memory, every access goes through mu and users are
+// stored and returned as copies so callers never share state across requests.
 type userRepository struct {
-	users      map[int]*models.User
-	swipes     []models.Swipe
-	nextUserID int
+	mu          sync.RWMutex
+	users       map[int]*models.User
+	userIDs     []int // sorted, backs paging by ID
+	locations   *gridIndex
+	swipes      []models.Swipe
+	nextUserID  int
+	nextSwipeID int
 }
 
 // NewUserRepository creates a new instance of userRepository.
 func NewUserRepository() UserRepository {
 	return &userRepository{
-		users:      make(map[int]*models.User),
-		swipes:     []models.Swipe{},
-		nextUserID: 1,
+		users:       make(map[int]*models.User),
+		locations:   newGridIndex(),
+		swipes:      []models.Swipe{},
+		nextUserID:  1,
+		nextSwipeID: 1,
 	}
 }
 
 // GenerateUserID generates the next unique user ID.
 func (r *userRepository) GenerateUserID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	id := r.nextUserID
 	r.nextUserID++
 	return id
@@ -42,27 +67,74 @@ func (r *userRepository) GenerateUserID() int {
 
 // GetAllUsers retrieves all users.
 func (r *userRepository) GetAllUsers() []*models.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var result []*models.User
 	for _, user := range r.users {
-		result = append(result, user)
+		result = append(result, copyUser(user))
+	}
+	return result
+}
+
+// GetUsersAfter retrieves up to limit users with an ID greater than afterID, ordered by ID.
+func (r *userRepository) GetUsersAfter(afterID int, limit int) []*models.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*models.User
+	for _, id := range r.userIDs[sort.SearchInts(r.userIDs, afterID+1):] {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, copyUser(r.users[id]))
+	}
+	return result
+}
+
+// GetUsersNear retrieves the users located within radiusKm of center.
+func (r *userRepository) GetUsersNear(center models.Location, radiusKm float64) []*models.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*models.User
+	for _, id := range r.locations.query(center, radiusKm) {
+		user := r.users[id]
+		if center.DistanceKm(*user.Location) <= radiusKm {
+			result = append(result, copyUser(user))
+		}
+	}
+	return result
+}
+
+// GetBoostedUsers retrieves the users whose boost window is open at now, ordered by ID.
+func (r *userRepository) GetBoostedUsers(now time.Time) []*models.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*models.User
+	for _, id := range r.userIDs {
+		if r.users[id].IsBoosted(now) {
+			result = append(result, copyUser(r.users[id]))
+		}
 	}
 	return result
 }
 
 // GetUserByID retrieves a user by their ID.
 func (r *userRepository) GetUserByID(userID int) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	user, exists := r.users[userID]
 	if !exists {
 		return nil, errors.New("user not found")
 	}
-	return user, nil
+	return copyUser(user), nil
 }
 
 // GetUserByEmail retrieves a user by their email.
 func (r *userRepository) GetUserByEmail(email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	for _, user := range r.users {
 		if user.Email == email {
-			return user, nil
+			return copyUser(user), nil
 		}
 	}
 	return nil, errors.New("user not found")
@@ -70,9 +142,11 @@ func (r *userRepository) GetUserByEmail(email string) (*models.User, error) {
 
 // GetUserByPhone retrieves a user by their phone number.
 func (r *userRepository) GetUserByPhone(phone string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	for _, user := range r.users {
 		if user.Phone == phone {
-			return user, nil
+			return copyUser(user), nil
 		}
 	}
 	return nil, errors.New("user not found")
@@ -80,25 +154,49 @@ func (r *userRepository) GetUserByPhone(phone string) (*models.User, error) {
 
 // SaveUser saves a new user.
 func (r *userRepository) SaveUser(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, exists := r.users[user.ID]; exists {
 		return errors.New("user ID already exists")
 	}
-	r.users[user.ID] = user
+	// Re-check uniqueness under the lock, the service level checks can race each other
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return ErrEmailAlreadyExists
+		}
+		if existing.Phone == user.Phone {
+			return ErrPhoneAlreadyExists
+		}
+	}
+	r.users[user.ID] = copyUser(user)
+	r.locations.set(user.ID, user.Location)
+	position := sort.SearchInts(r.userIDs, user.ID)
+	r.userIDs = append(r.userIDs[:position], append([]int{user.ID}, r.userIDs[position:]...)...)
 	r.nextUserID++
 	return nil
 }
 
 // UpdateUser updates an existing user.
 func (r *userRepository) UpdateUser(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, exists := r.users[user.ID]; !exists {
 		return errors.New("user not found")
 	}
-	r.users[user.ID] = user
+	for _, existing := range r.users {
+		if existing.ID != user.ID && existing.Phone == user.Phone {
+			return ErrPhoneAlreadyExists
+		}
+	}
+	r.users[user.ID] = copyUser(user)
+	r.locations.set(user.ID, user.Location)
 	return nil
 }
 
 // GetSwipesForUser retrieves all swipes for a specific user.
 func (r *userRepository) GetSwipesForUser(userID int) []models.Swipe {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var result []models.Swipe
 	for _, swipe := range r.swipes {
 		if swipe.UserID == userID {
@@ -108,13 +206,92 @@ func (r *userRepository) GetSwipesForUser(userID int) []models.Swipe {
 	return result
 }
 
-// SaveSwipe saves a swipe action.
+// GetSwipesReceived retrieves all swipes made on a specific user, oldest first.
+func (r *userRepository) GetSwipesReceived(targetUserID int) []models.Swipe {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []models.Swipe
+	for _, swipe := range r.swipes {
+		if swipe.TargetUserID == targetUserID {
+			result = append(result, swipe)
+		}
+	}
+	return result
+}
+
+// GetSwipeActivity summarises the swipes made by and on each of the given users up to asOf.
+func (r *userRepository) GetSwipeActivity(userIDs []int, asOf time.Time) (map[int]models.SwipeActivity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	wanted := map[int]bool{}
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	result := map[int]models.SwipeActivity{}
+	for _, swipe := range r.swipes {
+		if swipe.CreatedAt.After(asOf) {
+			continue
+		}
+		if wanted[swipe.TargetUserID] {
+			activity := result[swipe.TargetUserID]
+			activity.SwipesReceived++
+			if swipe.IsLike() {
+				activity.LikesReceived++
+			}
+			result[swipe.TargetUserID] = activity
+		}
+		if wanted[swipe.UserID] {
+			activity := result[swipe.UserID]
+			if swipe.CreatedAt.After(activity.LastSwipeAt) {
+				activity.LastSwipeAt = swipe.CreatedAt
+			}
+			result[swipe.UserID] = activity
+		}
+	}
+	return result, nil
+}
+
+// SaveSwipe saves a swipe action and assigns its ID.
 func (r *userRepository) SaveSwipe(swipe *models.Swipe) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	swipe.ID = r.nextSwipeID
+	r.nextSwipeID++
 	r.swipes = append(r.swipes, *swipe)
 	return nil
 }
 
+// DeleteSwipe removes a swipe.
+func (r *userRepository) DeleteSwipe(swipeID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, swipe := range r.swipes {
+		if swipe.ID == swipeID {
+			r.swipes = append(r.swipes[:i], r.swipes[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("swipe not found")
+}
+
 func (r *userRepository) ClearData() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.users = make(map[int]*models.User)
+	r.userIDs = nil
+	r.locations = newGridIndex()
 	r.nextUserID = 1
 }
+
+// copyUser returns a copy so the stored user can't be mutated outside the lock
+func copyUser(user *models.User) *models.User {
+	userCopy := *user
+	userCopy.Interests = append([]string(nil), user.Interests...)
+	userCopy.Preferences.InterestedIn = append([]string(nil), user.Preferences.InterestedIn...)
+	if user.Location != nil {
+		location := *user.Location
+		userCopy.Location = &location
+	}
+	return &userCopy
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+type VerificationRepository interface {
+	GenerateVerificationID() int
+	GetVerificationByID(verificationID int) (*models.VerificationRequest, error)
+	GetLatestVerificationForUser(userID int) (*models.VerificationRequest, error)
+	GetPendingVerifications() []models.VerificationRequest
+	SaveVerification(request *models.VerificationRequest) error
+	UpdateVerification(request *models.VerificationRequest) error
+}
+
+type verificationRepository struct {
+	mu                 sync.RWMutex
+	verifications      map[int]*models.VerificationRequest
+	nextVerificationID int
+}
+
+// NewVerificationRepository creates a new instance of verificationRepository.
+func NewVerificationRepository() VerificationRepository {
+	return &verificationRepository{
+		verifications:      make(map[int]*models.VerificationRequest),
+		nextVerificationID: 1,
+	}
+}
+
+// GenerateVerificationID generates the next unique verification request ID.
+func (r *verificationRepository) GenerateVerificationID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextVerificationID
+	r.nextVerificationID++
+	return id
+}
+
+// GetVerificationByID retrieves a verification request by its ID.
+func (r *verificationRepository) GetVerificationByID(verificationID int) (*models.VerificationRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	request, exists := r.verifications[verificationID]
+	if !exists {
+		return nil, errors.New("verification request not found")
+	}
+	requestCopy := *request
+	return &requestCopy, nil
+}
+
+// GetLatestVerificationForUser retrieves the most recent verification request of a user.
+func (r *verificationRepository) GetLatestVerificationForUser(userID int) (*models.VerificationRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var latest *models.VerificationRequest
+	for _, request := range r.verifications {
+		if request.UserID == userID && (latest == nil || request.ID > latest.ID) {
+			latest = request
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("verification request not found")
+	}
+	requestCopy := *latest
+	return &requestCopy, nil
+}
+
+// GetPendingVerifications retrieves the requests waiting for a review, oldest first.
+func (r *verificationRepository) GetPendingVerifications() []models.VerificationRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := []models.VerificationRequest{}
+	for _, request := range r.verifications {
+		if request.Status == models.VerificationStatusPending {
+			result = append(result, *request)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// SaveVerification saves a new verification request.
+func (r *verificationRepository) SaveVerification(request *models.VerificationRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.verifications[request.ID]; exists {
+		return errors.New("verification request ID already exists")
+	}
+	requestCopy := *request
+	r.verifications[request.ID] = &requestCopy
+	return nil
+}
+
+// UpdateVerification updates an existing verification request.
+func (r *verificationRepository) UpdateVerification(request *models.VerificationRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.verifications[request.ID]; !exists {
+		return errors.New("verification request not found")
+	}
+	requestCopy := *request
+	r.verifications[request.ID] = &requestCopy
+	return nil
+}
@@ -0,0 +1,79 @@
+package routes
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/events"
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/verification"
+)
+
+// Dependencies holds the repositories and services shared by the router and background jobs
+type Dependencies struct {
+	UserRepo         repositories.UserRepository
+	PlanRepo         repositories.PlanRepository
+	MatchRepo        repositories.MatchRepository
+	TokenRepo        repositories.TokenRepository
+	PremiumEventRepo repositories.PremiumEventRepository
+	PurchaseRepo     repositories.PurchaseRepository
+	IdempotencyRepo  repositories.IdempotencyRepository
+	VerificationRepo repositories.VerificationRepository
+	MessageRepo      repositories.MessageRepository
+	NotificationRepo repositories.NotificationRepository
+
+	PaymentProvider     payments.PaymentProvider
+	VerificationChecker verification.Checker
+
+	// Events delivers account events to the connected clients, the services also publish them
+	// to the notification center
+	Events *events.Hub
+
+	// AdminAPIKey protects the admin endpoints, they are disabled while it is empty
+	AdminAPIKey string
+
+	TokenService   services.TokenService
+	UserService    services.UserService
+	SwipeService   services.SwipeService
+	MatchService   services.MatchService
+	PaymentService services.PaymentService
+	ChatService    services.ChatService
+
+	NotificationService services.NotificationService
+
+	VerificationService services.VerificationService
+}
+
+// NewDependencies wires the services on top of the given user repository, plan catalogue,
+// payment provider, verification checker and candidate ranking weights. Matches, purchases and
+// notifications are kept next to the users, the remaining stores are kept in memory
+func NewDependencies(userRepo repositories.UserRepository, plans []models.Plan, paymentProvider payments.PaymentProvider, verificationChecker verification.Checker, rankingWeights services.RankingWeights) *Dependencies {
+	d := &Dependencies{
+		UserRepo:         userRepo,
+		PlanRepo:         repositories.NewPlanRepository(plans),
+		PurchaseRepo:     repositories.NewPurchaseRepositoryFor(userRepo),
+		IdempotencyRepo:  repositories.NewIdempotencyRepository(),
+		PaymentProvider:  paymentProvider,
+		MatchRepo:        repositories.NewMatchRepositoryFor(userRepo),
+		TokenRepo:        repositories.NewTokenRepository(),
+		PremiumEventRepo: repositories.NewPremiumEventRepository(),
+		VerificationRepo: repositories.NewVerificationRepository(),
+		MessageRepo:      repositories.NewMessageRepository(),
+		NotificationRepo: repositories.NewNotificationRepositoryFor(userRepo),
+
+		VerificationChecker: verificationChecker,
+		Events:              events.NewHub(),
+	}
+
+	d.NotificationService = services.NewNotificationService(d.UserRepo, d.NotificationRepo)
+	publisher := services.NewEventFanOut(d.NotificationService, d.Events)
+
+	d.TokenService = services.NewTokenService(d.TokenRepo)
+	d.UserService = services.NewUserServiceWith(d.UserRepo, d.PlanRepo, d.PremiumEventRepo, d.TokenService, publisher)
+	d.SwipeService = services.NewSwipeServiceWith(d.UserRepo, d.MatchRepo, services.NewRankerWithWeights(rankingWeights), publisher)
+	d.MatchService = services.NewMatchService(d.UserRepo, d.MatchRepo)
+	d.ChatService = services.NewChatService(d.MatchRepo, d.MessageRepo)
+	d.PaymentService = services.NewPaymentService(d.UserRepo, d.PlanRepo, d.PurchaseRepo, d.PaymentProvider, d.UserService)
+	d.VerificationService = services.NewVerificationService(d.UserRepo, d.VerificationRepo, d.VerificationChecker, d.UserService)
+	return d
+}
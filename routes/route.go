@@ -1,60 +1,98 @@
 package routes
 
 import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/config"
 	"github.com/GradiyantoS/go-dealls-test-app/controllers"
 	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
 	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/verification"
 	"github.com/gorilla/mux"
 )
 
-func SetupRouter() *mux.Router {
-	userRepo := repositories.NewUserRepository()
-
-	userService := services.NewUserService(userRepo)
-	swipeService := services.NewSwipeService(userRepo)
-
-	authController := controllers.NewAuthController(userService)
-	userController := controllers.NewUserController(userService, swipeService)
-
-	// Create a new router
-	router := mux.NewRouter()
-
-	// Public routes
-	router.HandleFunc("/signup", authController.SignUp).Methods("POST")
-	router.HandleFunc("/login", authController.Login).Methods("POST")
+// idempotencyTTL is how long a response is replayed for retries using the same Idempotency-Key
+const idempotencyTTL = 24 * time.Hour
 
-	// Protected routes (requires JWT authentication)
-	protected := router.PathPrefix("/").Subrouter()
-	protected.Use(middlewares.AuthMiddleware)
-
-	protected.HandleFunc("/purchase-premium", userController.PurchasePremium).Methods("POST")
-	protected.HandleFunc("/swipe", userController.SwipeHandler).Methods("POST")
-	protected.HandleFunc("/candidates", userController.SwipeCandidates).Methods("GET")
-
-	return router
+func SetupRouter() *mux.Router {
+	return SetupRouterWithRepo(repositories.NewUserRepository())
 }
 
+// SetupRouterWithRepo builds the router on top of userRepo with the bundled plan catalogue,
+// the fake payment provider and manual verification reviews
 func SetupRouterWithRepo(userRepo repositories.UserRepository) *mux.Router {
+	plans, err := config.LoadPlans("")
+	if err != nil {
+		panic(err) // The bundled catalogue is validated by the tests, this can't happen
+	}
+	provider := payments.NewFakeProvider(os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+	deps := NewDependencies(userRepo, plans, provider, verification.NewManualReview(), services.DefaultRankingWeights)
+	deps.AdminAPIKey = os.Getenv("ADMIN_API_KEY")
+	return SetupRouterWithDependencies(deps)
+}
 
-	userService := services.NewUserService(userRepo)
-	swipeService := services.NewSwipeService(userRepo)
-	authController := controllers.NewAuthController(userService)
-	userController := controllers.NewUserController(userService, swipeService)
+func SetupRouterWithDependencies(deps *Dependencies) *mux.Router {
+	authController := controllers.NewAuthController(deps.UserService, deps.TokenService)
+	userController := controllers.NewUserController(deps.UserService, deps.SwipeService)
+	matchController := controllers.NewMatchController(deps.MatchService)
+	paymentController := controllers.NewPaymentController(deps.PaymentService)
+	verificationController := controllers.NewVerificationController(deps.VerificationService)
+	conversationController := controllers.NewConversationController(deps.ChatService)
+	realtimeController := controllers.NewRealtimeController(deps.Events)
+	notificationController := controllers.NewNotificationController(deps.NotificationService)
 
+	// Create a new router
 	router := mux.NewRouter()
 
 	// Public routes
 	router.HandleFunc("/signup", authController.SignUp).Methods("POST")
 	router.HandleFunc("/login", authController.Login).Methods("POST")
+	router.HandleFunc("/token/refresh", authController.RefreshToken).Methods("POST")
+	router.HandleFunc("/plans", userController.GetPlans).Methods("GET")
+	router.HandleFunc("/webhooks/payments", paymentController.PaymentWebhook).Methods("POST")
 
-	// Protected routes
-	protected := router.PathPrefix("/").Subrouter()
-	protected.Use(middlewares.AuthMiddleware)
+	// Live event streams, authenticated like the protected routes
+	streamAuth := middlewares.StreamAuthMiddleware(deps.TokenService)
+	router.Handle("/ws", streamAuth(http.HandlerFunc(realtimeController.WebSocket))).Methods("GET")
+	router.Handle("/events", streamAuth(http.HandlerFunc(realtimeController.Events))).Methods("GET")
 
-	protected.HandleFunc("/purchase-premium", userController.PurchasePremium).Methods("POST")
-	protected.HandleFunc("/swipe", userController.SwipeHandler).Methods("POST")
+	// Protected routes (requires JWT authentication)
+	protected := router.PathPrefix("/").Subrouter()
+	protected.Use(middlewares.AuthMiddleware(deps.TokenService))
+
+	protected.HandleFunc("/logout", authController.Logout).Methods("POST")
+	protected.HandleFunc("/me", userController.GetProfile).Methods("GET")
+	protected.HandleFunc("/me", userController.UpdateProfile).Methods("PATCH")
+	protected.HandleFunc("/me/location", userController.UpdateLocation).Methods("PUT")
+	protected.HandleFunc("/boost", userController.BoostProfile).Methods("POST")
+	protected.HandleFunc("/me/verification", verificationController.SubmitVerification).Methods("POST")
+	protected.HandleFunc("/me/verification", verificationController.GetVerificationStatus).Methods("GET")
+	// Retried purchases, swipes, rewinds and messages must not be applied twice
+	idempotent := middlewares.Idempotency(deps.IdempotencyRepo, idempotencyTTL)
+
+	protected.Handle("/purchase-premium", idempotent(http.HandlerFunc(paymentController.PurchasePremium))).Methods("POST")
+	protected.Handle("/swipe", idempotent(http.HandlerFunc(userController.SwipeHandler))).Methods("POST")
+	protected.Handle("/swipe/rewind", idempotent(http.HandlerFunc(userController.RewindSwipe))).Methods("POST")
 	protected.HandleFunc("/candidates", userController.SwipeCandidates).Methods("GET")
+	protected.HandleFunc("/likes/received", userController.GetReceivedLikes).Methods("GET")
+	protected.HandleFunc("/matches", matchController.GetMatches).Methods("GET")
+	protected.HandleFunc("/matches/{id:[0-9]+}", matchController.Unmatch).Methods("DELETE")
+	protected.Handle("/conversations/{id:[0-9]+}/messages", idempotent(http.HandlerFunc(conversationController.SendMessage))).Methods("POST")
+	protected.HandleFunc("/conversations/{id:[0-9]+}/messages", conversationController.GetMessages).Methods("GET")
+	protected.HandleFunc("/notifications", notificationController.GetNotifications).Methods("GET")
+	protected.HandleFunc("/notifications/read", notificationController.MarkRead).Methods("POST")
+
+	// Admin routes (requires the admin API key)
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(middlewares.AdminMiddleware(deps.AdminAPIKey))
+
+	admin.HandleFunc("/verifications", verificationController.GetPendingVerifications).Methods("GET")
+	admin.HandleFunc("/verifications/{id:[0-9]+}/document", verificationController.GetVerificationDocument).Methods("GET")
+	admin.HandleFunc("/verifications/{id:[0-9]+}/review", verificationController.ReviewVerification).Methods("POST")
 
 	return router
 }
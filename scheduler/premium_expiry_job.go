@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+)
+
+// PremiumExpiryJob clears premium features of users whose subscription has expired and warns
+// users whose subscription is about to expire
+type PremiumExpiryJob struct {
+	userService services.UserService
+}
+
+// NewPremiumExpiryJob creates a PremiumExpiryJob
+func NewPremiumExpiryJob(userService services.UserService) *PremiumExpiryJob {
+	return &PremiumExpiryJob{userService}
+}
+
+func (j *PremiumExpiryJob) Name() string {
+	return "premium-expiry"
+}
+
+func (j *PremiumExpiryJob) Run(now time.Time) error {
+	expired, err := j.userService.ExpirePremiumFeatures(now)
+	if expired > 0 {
+		log.Printf("Scheduler: expired premium features of %d user(s)", expired)
+	}
+	if err != nil {
+		return err
+	}
+
+	warned, err := j.userService.NotifyExpiringPremium(now)
+	if warned > 0 {
+		log.Printf("Scheduler: warned %d user(s) of their premium expiring", warned)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+)
+
+// QuotaResetJob notifies users once the daily swipe quotas reset at midnight UTC
+type QuotaResetJob struct {
+	swipeService services.SwipeService
+	lastDay      time.Time
+}
+
+// NewQuotaResetJob creates a QuotaResetJob, the first reset it reports is the next midnight
+func NewQuotaResetJob(swipeService services.SwipeService) *QuotaResetJob {
+	return &QuotaResetJob{swipeService: swipeService}
+}
+
+func (j *QuotaResetJob) Name() string {
+	return "quota-reset"
+}
+
+func (j *QuotaResetJob) Run(now time.Time) error {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if j.lastDay.IsZero() {
+		j.lastDay = day
+		return nil
+	}
+	if !day.After(j.lastDay) {
+		return nil
+	}
+	j.lastDay = day
+
+	notified, err := j.swipeService.NotifyQuotaReset(day)
+	if err != nil {
+		return err
+	}
+	if notified > 0 {
+		log.Printf("Scheduler: notified %d user(s) of the daily quota reset", notified)
+	}
+	return nil
+}
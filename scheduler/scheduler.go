@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work, Run receives the time of the tick it was triggered by
+type Job interface {
+	Name() string
+	Run(now time.Time) error
+}
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+}
+
+// Scheduler runs registered jobs periodically, each job in its own goroutine
+type Scheduler struct {
+	jobs   []scheduledJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates an empty Scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job to run once per interval, jobs must be registered before Start
+func (s *Scheduler) Every(interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{job: job, interval: interval})
+}
+
+// Start runs every job once immediately and then on each tick until ctx is done or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, scheduled := range s.jobs {
+		s.wg.Add(1)
+		go func(scheduled scheduledJob) {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(scheduled.interval)
+			defer ticker.Stop()
+
+			runJob(scheduled.job, time.Now())
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					runJob(scheduled.job, now)
+				}
+			}
+		}(scheduled)
+	}
+}
+
+// Stop cancels all jobs and waits for running ones to finish
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func runJob(job Job, now time.Time) {
+	if err := job.Run(now); err != nil {
+		log.Printf("Scheduler: job %s failed: %v", job.Name(), err)
+	}
+}
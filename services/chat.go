@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+)
+
+type ChatService interface {
+	SendMessage(userID int, conversationID int, body string) (*models.Message, error)
+	GetMessages(userID int, conversationID int, cursor string, limit int) (*models.MessagePage, error)
+}
+
+const (
+	// MaxMessageLength is the maximum number of characters of a message
+	MaxMessageLength = 1000
+
+	DefaultMessagePageSize = 50
+	MaxMessagePageSize     = 100
+)
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrInvalidMessage       = errors.New("message must be between 1 and 1000 characters")
+)
+
+type chatService struct {
+	matchRepo         repositories.MatchRepository
+	messageRepo       repositories.MessageRepository
+	conversationLocks keyedMutex
+}
+
+// NewChatService creates a chat service, every match gets a conversation with the match's ID
+func NewChatService(matchRepo repositories.MatchRepository, messageRepo repositories.MessageRepository) ChatService {
+	return &chatService{matchRepo: matchRepo, messageRepo: messageRepo}
+}
+
+// SendMessage posts a message to the conversation, the conversation is started by its first message
+func (s *chatService) SendMessage(userID int, conversationID int, body string) (*models.Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxMessageLength {
+		return nil, ErrInvalidMessage
+	}
+
+	match, err := s.matchFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	unlock := s.conversationLocks.Lock(conversationID)
+	defer unlock()
+	if _, err := s.messageRepo.GetConversationByID(conversationID); err != nil {
+		conversation := &models.Conversation{ID: match.ID, UserID: match.UserID, OtherUserID: match.MatchedID, CreatedAt: now}
+		if err := s.messageRepo.SaveConversation(conversation); err != nil {
+			return nil, err
+		}
+	}
+
+	message := &models.Message{
+		ID:             s.messageRepo.GenerateMessageID(),
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Body:           body,
+		CreatedAt:      now,
+	}
+	if err := s.messageRepo.SaveMessage(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// GetMessages retrieves a page of the conversation, newest first
+func (s *chatService) GetMessages(userID int, conversationID int, cursor string, limit int) (*models.MessagePage, error) {
+	if _, err := s.matchFor(userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	messages, next, err := pageBefore(cursor, limit, DefaultMessagePageSize, MaxMessagePageSize,
+		func(beforeID int, limit int) []models.Message {
+			return s.messageRepo.GetMessagesBefore(conversationID, beforeID, limit)
+		},
+		func(message models.Message) int { return message.ID })
+	if err != nil {
+		return nil, err
+	}
+	return &models.MessagePage{Messages: messages, NextCursor: next}, nil
+}
+
+// matchFor returns the match behind a conversation, only the two users who liked each other may
+// use it. Unmatching ends the conversation.
+func (s *chatService) matchFor(userID int, conversationID int) (*models.Match, error) {
+	match, err := s.matchRepo.GetMatchByID(conversationID)
+	// Hide conversations of other users behind the same error as a missing one
+	if err != nil || !match.HasUser(userID) {
+		return nil, ErrConversationNotFound
+	}
+	return match, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Segments of a deck, profiles that super liked the user come first, then the boosted ones and
+// then everybody else
+const (
+	segmentSuperLiked = iota
+	segmentBoosted
+	segmentRegular
+)
+
+// deckPosition is where a profile sits in the order a deck segment is read in, by distance and
+// then ID when the segment is ordered by distance, by ID otherwise
+type deckPosition struct {
+	DistanceKm *float64 `json:"d,omitempty"`
+	ID         int      `json:"id"`
+}
+
+// before reports whether the position is read before other
+func (p deckPosition) before(other deckPosition) bool {
+	if p.DistanceKm != nil && other.DistanceKm != nil && *p.DistanceKm != *other.DistanceKm {
+		return *p.DistanceKm < *other.DistanceKm
+	}
+	return p.ID < other.ID
+}
+
+// deckCursor is the position of the last profile of a deck page. Segments are read in windows
+// that are ranked best first, the cursor holds the bounds of the last profile's window, after
+// its start and up to its end, and the profile's score and position to continue within it.
+// RankedAt is when the first page was ranked, later pages score with the same time and activity
+// so the scores don't drift between pages.
+type deckCursor struct {
+	Segment  int          `json:"g"`
+	After    deckPosition `json:"a"`
+	Through  deckPosition `json:"z"`
+	Score    float64      `json:"r"`
+	Last     deckPosition `json:"p"`
+	RankedAt int64        `json:"t"`
+}
+
+// after reports whether a profile of the cursor's window comes after the cursor
+func (c deckCursor) after(score float64, position deckPosition) bool {
+	if score != c.Score {
+		return score < c.Score
+	}
+	return c.Last.before(position)
+}
+
+// encodeCursor turns a cursor into the opaque string handed to clients
+func encodeCursor(cursor deckCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses a cursor from encodeCursor, an empty string is the start of the deck
+func decodeCursor(value string) (deckCursor, error) {
+	var cursor deckCursor
+	if value == "" {
+		return cursor, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cursor); err != nil || cursor.Last.ID <= 0 || cursor.Through.ID <= 0 ||
+		cursor.RankedAt <= 0 || cursor.Segment < segmentSuperLiked || cursor.Segment > segmentRegular {
+		return deckCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// idCursor is the position of the last item of a page served newest first, like messages, the
+// next page continues before its ID
+type idCursor struct {
+	ID int `json:"id"`
+}
+
+// encodeIDCursor turns a cursor into the opaque string handed to clients
+func encodeIDCursor(cursor idCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeIDCursor parses a cursor from encodeIDCursor, an empty string is the newest item.
+// Cursors of other lists, e.g. a deck cursor, are rejected.
+func decodeIDCursor(value string) (idCursor, error) {
+	var cursor idCursor
+	if value == "" {
+		return cursor, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cursor); err != nil || cursor.ID <= 0 {
+		return idCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// pageBefore serves a page of a list read newest first. fetch returns up to limit items with an
+// ID lower than beforeID, the newest ones for 0, and id tells an item's ID. limit is clamped to
+// maxLimit and falls back to defaultLimit when it isn't set.
+func pageBefore[T any](cursor string, limit, defaultLimit, maxLimit int,
+	fetch func(beforeID int, limit int) []T, id func(T) int) ([]T, string, error) {
+	position, err := decodeIDCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	// One extra item tells whether there is a next page
+	items := fetch(position.ID, limit+1)
+	if len(items) <= limit {
+		return items, "", nil
+	}
+	return items[:limit], encodeIDCursor(idCursor{ID: id(items[limit-1])}), nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import "github.com/GradiyantoS/go-dealls-test-app/models"
+
+// EventPublisher delivers account events to the user they are about, e.g. to live connections.
+// Publish must not block, it is called while the services hold their per-user locks.
+type EventPublisher interface {
+	Publish(event models.Event)
+}
+
+// discardEvents is the publisher of services created without one
+type discardEvents struct{}
+
+func (discardEvents) Publish(models.Event) {}
+
+// fanOut hands every event to each of its publishers in turn
+type fanOut []EventPublisher
+
+// NewEventFanOut creates a publisher handing every event to each of the given publishers
+func NewEventFanOut(publishers ...EventPublisher) EventPublisher {
+	return fanOut(publishers)
+}
+
+func (f fanOut) Publish(event models.Event) {
+	for _, publisher := range f {
+		publisher.Publish(event)
+	}
+}
@@ -0,0 +1,17 @@
+package services
+
+import "sync"
+
+// keyedMutex serialises work per key, e.g. every swipe made by the same user,
+// so read-check-write sequences in the services can't interleave for that key.
+type keyedMutex struct {
+	locks sync.Map // map[int]*sync.Mutex
+}
+
+// Lock acquires the mutex for key and returns the function releasing it.
+func (k *keyedMutex) Lock(key int) func() {
+	value, _ := k.locks.LoadOrStore(key, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
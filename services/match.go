@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+)
+
+type MatchService interface {
+	GetMatches(userID int) ([]models.Match, error)
+	Unmatch(userID int, matchID int) error
+}
+
+type matchService struct {
+	userRepo  repositories.UserRepository
+	matchRepo repositories.MatchRepository
+}
+
+func NewMatchService(userRepo repositories.UserRepository, matchRepo repositories.MatchRepository) MatchService {
+	return &matchService{userRepo, matchRepo}
+}
+
+// GetMatches retrieves every match the user is part of
+func (s *matchService) GetMatches(userID int) ([]models.Match, error) {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	matches := s.matchRepo.GetMatchesForUser(userID)
+	if matches == nil {
+		matches = []models.Match{}
+	}
+	return matches, nil
+}
+
+// Unmatch removes a match, only either side of the match is allowed to do so
+func (s *matchService) Unmatch(userID int, matchID int) error {
+	match, err := s.matchRepo.GetMatchByID(matchID)
+	if err != nil {
+		return err
+	}
+
+	// Hide matches of other users behind the same error as a missing one
+	if !match.HasUser(userID) {
+		return errors.New("match not found")
+	}
+
+	return s.matchRepo.DeleteMatch(matchID, time.Now().UTC())
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"log"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+)
+
+// NotificationService keeps the events users should find later in their notification center, it
+// receives them as an EventPublisher next to the live connections
+type NotificationService interface {
+	EventPublisher
+	GetNotifications(userID int, cursor string, limit int) (*models.NotificationPage, error)
+	MarkRead(userID int, notificationIDs []int) (int, error)
+	MarkAllRead(userID int) (int, error)
+}
+
+const (
+	DefaultNotificationPageSize = 20
+	MaxNotificationPageSize     = 50
+)
+
+// ErrNoNotificationIDs is returned when notifications are to be marked as read without saying which
+var ErrNoNotificationIDs = errors.New("ids must not be empty, use all to mark every notification as read")
+
+// notifiedEvents are the event types kept as notifications, the others only matter while connected
+var notifiedEvents = map[string]bool{
+	models.EventLikeReceived:     true,
+	models.EventMutualLike:       true,
+	models.EventPremiumActivated: true,
+	models.EventPremiumExpiring:  true,
+	models.EventPremiumExpired:   true,
+}
+
+type notificationService struct {
+	userRepo         repositories.UserRepository
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewNotificationService(userRepo repositories.UserRepository, notificationRepo repositories.NotificationRepository) NotificationService {
+	return &notificationService{userRepo, notificationRepo}
+}
+
+// Publish stores the event as an unread notification if its type is kept
+func (s *notificationService) Publish(event models.Event) {
+	if !notifiedEvents[event.Type] {
+		return
+	}
+
+	notification := &models.Notification{
+		UserID:    event.UserID,
+		Type:      event.Type,
+		Data:      event.Data,
+		CreatedAt: event.CreatedAt,
+	}
+	// Publishing can't fail the action that caused the event
+	if err := s.notificationRepo.SaveNotification(notification); err != nil {
+		log.Printf("Failed to save %s notification for user %d: %v", event.Type, event.UserID, err)
+	}
+}
+
+// GetNotifications retrieves a page of the user's notifications, newest first, together with the
+// number of unread ones
+func (s *notificationService) GetNotifications(userID int, cursor string, limit int) (*models.NotificationPage, error) {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	notifications, next, err := pageBefore(cursor, limit, DefaultNotificationPageSize, MaxNotificationPageSize,
+		func(beforeID int, limit int) []models.Notification {
+			return s.notificationRepo.GetNotificationsBefore(userID, beforeID, limit)
+		},
+		func(notification models.Notification) int { return notification.ID })
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationPage{
+		Notifications: notifications,
+		NextCursor:    next,
+		UnreadCount:   s.notificationRepo.CountUnreadNotifications(userID),
+	}, nil
+}
+
+// MarkRead marks the given notifications of the user as read and returns how many unread
+// notifications are left
+func (s *notificationService) MarkRead(userID int, notificationIDs []int) (int, error) {
+	if len(notificationIDs) == 0 {
+		return 0, ErrNoNotificationIDs
+	}
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return 0, err
+	}
+
+	s.notificationRepo.MarkNotificationsRead(userID, notificationIDs)
+	return s.notificationRepo.CountUnreadNotifications(userID), nil
+}
+
+// MarkAllRead marks every notification of the user as read, none are left unread
+func (s *notificationService) MarkAllRead(userID int) (int, error) {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return 0, err
+	}
+
+	s.notificationRepo.MarkAllNotificationsRead(userID)
+	return s.notificationRepo.CountUnreadNotifications(userID), nil
+}
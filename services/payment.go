@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+)
+
+type PaymentService interface {
+	StartPurchase(userID int, planID string) (*models.Purchase, error)
+	HandleWebhook(payload []byte, signature string) error
+}
+
+type paymentService struct {
+	userRepo      repositories.UserRepository
+	planRepo      repositories.PlanRepository
+	purchaseRepo  repositories.PurchaseRepository
+	provider      payments.PaymentProvider
+	userService   UserService
+	purchaseLocks keyedMutex
+}
+
+func NewPaymentService(userRepo repositories.UserRepository, planRepo repositories.PlanRepository, purchaseRepo repositories.PurchaseRepository, provider payments.PaymentProvider, userService UserService) PaymentService {
+	return &paymentService{
+		userRepo:     userRepo,
+		planRepo:     planRepo,
+		purchaseRepo: purchaseRepo,
+		provider:     provider,
+		userService:  userService,
+	}
+}
+
+// StartPurchase charges the plan price and stores a pending purchase, premium is only
+// activated once the provider confirms the payment through the webhook
+func (s *paymentService) StartPurchase(userID int, planID string) (*models.Purchase, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsInactive {
+		return nil, errors.New("user account is inactive")
+	}
+
+	plan, err := s.planRepo.GetPlanByID(planID)
+	if err != nil {
+		return nil, err
+	}
+
+	purchaseID := s.purchaseRepo.GeneratePurchaseID()
+	charge, err := s.provider.CreateCharge(plan.Price, plan.Currency, "purchase-"+strconv.Itoa(purchaseID))
+	if err != nil {
+		return nil, errors.New("failed to create payment")
+	}
+
+	now := time.Now()
+	purchase := &models.Purchase{
+		ID:        purchaseID,
+		UserID:    userID,
+		PlanID:    plan.ID,
+		ChargeID:  charge.ID,
+		Amount:    plan.Price,
+		Currency:  plan.Currency,
+		Status:    models.PurchaseStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.purchaseRepo.SavePurchase(purchase); err != nil {
+		return nil, err
+	}
+	return purchase, nil
+}
+
+// HandleWebhook verifies a provider callback and settles the matching pending purchase,
+// callbacks for purchases that are already settled are ignored so retries are harmless
+func (s *paymentService) HandleWebhook(payload []byte, signature string) error {
+	if err := s.provider.VerifyWebhookSignature(payload, signature); err != nil {
+		return payments.ErrInvalidSignature
+	}
+
+	var event payments.WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.ChargeID == "" {
+		return errors.New("invalid webhook payload")
+	}
+
+	purchase, err := s.purchaseRepo.GetPurchaseByChargeID(event.ChargeID)
+	if err != nil {
+		return err
+	}
+
+	unlock := s.purchaseLocks.Lock(purchase.ID)
+	defer unlock()
+
+	// Reload under the lock, a concurrent delivery of the same event may have settled it
+	purchase, err = s.purchaseRepo.GetPurchaseByID(purchase.ID)
+	if err != nil {
+		return err
+	}
+	if purchase.Status != models.PurchaseStatusPending {
+		return nil
+	}
+
+	switch event.Type {
+	case payments.WebhookChargeSucceeded:
+		return s.completePurchase(purchase)
+	case payments.WebhookChargeFailed:
+		return s.settlePurchase(purchase, models.PurchaseStatusFailed, "payment failed")
+	default:
+		return errors.New("unsupported webhook event: " + event.Type)
+	}
+}
+
+func (s *paymentService) completePurchase(purchase *models.Purchase) error {
+	if _, err := s.provider.CaptureCharge(purchase.ChargeID); err != nil {
+		return s.settlePurchase(purchase, models.PurchaseStatusFailed, "capture failed: "+err.Error())
+	}
+
+	if _, err := s.userService.PurchasePlan(purchase.UserID, purchase.PlanID); err != nil {
+		// The money was taken but premium couldn't be granted, give it back
+		if _, refundErr := s.provider.RefundCharge(purchase.ChargeID); refundErr != nil {
+			return errors.New("failed to refund charge " + purchase.ChargeID + ": " + refundErr.Error())
+		}
+		return s.settlePurchase(purchase, models.PurchaseStatusRefunded, err.Error())
+	}
+
+	return s.settlePurchase(purchase, models.PurchaseStatusPaid, "")
+}
+
+func (s *paymentService) settlePurchase(purchase *models.Purchase, status string, reason string) error {
+	purchase.Status = status
+	purchase.FailureReason = reason
+	purchase.UpdatedAt = time.Now()
+	return s.purchaseRepo.UpdatePurchase(purchase)
+}
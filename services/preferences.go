@@ -0,0 +1,77 @@
+package services
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// interestedGenders returns the genders the user wants to see. Users who haven't set
+// preferences yet keep the original behaviour of seeing the opposite gender.
+func interestedGenders(user *models.User) []string {
+	if len(user.Preferences.InterestedIn) > 0 {
+		return user.Preferences.InterestedIn
+	}
+	switch user.Gender {
+	case "male":
+		return []string{"female"}
+	case "female":
+		return []string{"male"}
+	}
+	return nil
+}
+
+// wantsToSee reports whether the candidate fits the seeker's preferences
+func wantsToSee(seeker, candidate *models.User, distanceKm *float64, now time.Time) bool {
+	return wantsGender(seeker, candidate.Gender) &&
+		inAgeRange(seeker.Preferences, candidate, now) &&
+		withinDistance(seeker.Preferences, distanceKm)
+}
+
+func wantsGender(seeker *models.User, gender string) bool {
+	for _, interested := range interestedGenders(seeker) {
+		if interested == gender {
+			return true
+		}
+	}
+	return false
+}
+
+// inAgeRange reports whether the candidate's age is within the range, candidates without
+// a birthdate can't be placed in a range and are only shown when no range is set
+func inAgeRange(preferences models.Preferences, candidate *models.User, now time.Time) bool {
+	if preferences.MinAge == 0 && preferences.MaxAge == 0 {
+		return true
+	}
+	if candidate.Birthdate.IsZero() {
+		return false
+	}
+	age := candidate.Birthdate.AgeAt(now)
+	if preferences.MinAge != 0 && age < preferences.MinAge {
+		return false
+	}
+	return preferences.MaxAge == 0 || age <= preferences.MaxAge
+}
+
+// withinDistance reports whether the distance is within the preferred maximum, an unknown
+// distance only fits when no maximum is set
+func withinDistance(preferences models.Preferences, distanceKm *float64) bool {
+	if preferences.MaxDistanceKm == 0 {
+		return true
+	}
+	return distanceKm != nil && *distanceKm <= float64(preferences.MaxDistanceKm)
+}
+
+// distanceBetween returns the distance between two users, nil if either has no location
+func distanceBetween(user, other *models.User) *float64 {
+	if user.Location == nil || other.Location == nil {
+		return nil
+	}
+	distance := user.Location.DistanceKm(*other.Location)
+	return &distance
+}
+
+// isMutuallyCompatible reports whether both users fit each other's preferences
+func isMutuallyCompatible(user, candidate *models.User, distanceKm *float64, now time.Time) bool {
+	return wantsToSee(user, candidate, distanceKm, now) && wantsToSee(candidate, user, distanceKm, now)
+}
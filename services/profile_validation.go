@@ -0,0 +1,144 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+const (
+	maxNameLength      = 100
+	maxBioLength       = 500
+	maxOccupationChars = 100
+	maxInterests       = 10
+	maxInterestLength  = 30
+	minAge             = 18
+	maxAge             = 100
+)
+
+var phoneRegex = regexp.MustCompile(`^\+?[0-9]{6,20}$`)
+
+var validGenders = map[string]bool{
+	"male":   true,
+	"female": true,
+}
+
+func validateProfileUpdate(update models.ProfileUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return errors.New("name must not be empty")
+		}
+		if utf8.RuneCountInString(name) > maxNameLength {
+			return errors.New("name is too long")
+		}
+	}
+	if update.Phone != nil && !phoneRegex.MatchString(*update.Phone) {
+		return errors.New("invalid phone number")
+	}
+	if update.Gender != nil && !validGenders[*update.Gender] {
+		return errors.New("invalid gender")
+	}
+	if update.Bio != nil && utf8.RuneCountInString(*update.Bio) > maxBioLength {
+		return errors.New("bio is too long")
+	}
+	if update.Occupation != nil && utf8.RuneCountInString(*update.Occupation) > maxOccupationChars {
+		return errors.New("occupation is too long")
+	}
+	if update.Interests != nil {
+		if len(*update.Interests) > maxInterests {
+			return errors.New("too many interests")
+		}
+		for _, interest := range *update.Interests {
+			interest = strings.TrimSpace(interest)
+			if interest == "" || utf8.RuneCountInString(interest) > maxInterestLength {
+				return errors.New("invalid interest: " + interest)
+			}
+		}
+	}
+	if update.Preferences != nil {
+		return validatePreferences(*update.Preferences)
+	}
+	return nil
+}
+
+// validateSignUpProfile applies the profile update rules to the profile fields of a new account,
+// preferences are optional at signup and only checked when given
+func validateSignUpProfile(user *models.User) error {
+	update := models.ProfileUpdate{
+		Bio:        &user.Bio,
+		Occupation: &user.Occupation,
+		Interests:  &user.Interests,
+	}
+	preferences := user.Preferences
+	if len(preferences.InterestedIn) > 0 || preferences.MinAge != 0 || preferences.MaxAge != 0 || preferences.MaxDistanceKm != 0 {
+		update.Preferences = &preferences
+	}
+	return validateProfileUpdate(update)
+}
+
+// validateBirthdate enforces the legal minimum age for having an account
+func validateBirthdate(birthdate models.Date, now time.Time) error {
+	if birthdate.IsZero() {
+		return errors.New("birthdate is required")
+	}
+	if birthdate.After(now) {
+		return errors.New("birthdate must not be in the future")
+	}
+	if birthdate.AgeAt(now) < minAge {
+		return errors.New("you must be at least 18 years old")
+	}
+	return nil
+}
+
+func validateLocation(location models.Location) error {
+	if location.Latitude < -90 || location.Latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if location.Longitude < -180 || location.Longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+func validatePreferences(preferences models.Preferences) error {
+	if len(preferences.InterestedIn) == 0 {
+		return errors.New("interested_in must contain at least one gender")
+	}
+	for _, gender := range preferences.InterestedIn {
+		if !validGenders[gender] {
+			return errors.New("invalid gender in interested_in: " + gender)
+		}
+	}
+	if preferences.MinAge != 0 && (preferences.MinAge < minAge || preferences.MinAge > maxAge) {
+		return errors.New("min_age must be between 18 and 100")
+	}
+	if preferences.MaxAge != 0 && (preferences.MaxAge < minAge || preferences.MaxAge > maxAge) {
+		return errors.New("max_age must be between 18 and 100")
+	}
+	if preferences.MinAge != 0 && preferences.MaxAge != 0 && preferences.MinAge > preferences.MaxAge {
+		return errors.New("min_age must not be greater than max_age")
+	}
+	if preferences.MaxDistanceKm < 0 {
+		return errors.New("max_distance_km must not be negative")
+	}
+	return nil
+}
+
+// normalizeInterests lowercases and de-duplicates interests so they can be compared between users
+func normalizeInterests(interests []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, interest := range interests {
+		interest = strings.ToLower(strings.TrimSpace(interest))
+		if !seen[interest] {
+			seen[interest] = true
+			result = append(result, interest)
+		}
+	}
+	return result
+}
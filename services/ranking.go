@@ -0,0 +1,183 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+)
+
+// ScoringContext is everything a Scorer gets to know about a candidate
+type ScoringContext struct {
+	Viewer     *models.User
+	Candidate  *models.User
+	DistanceKm *float64 // nil when either user has no location
+	Activity   models.SwipeActivity
+	Now        time.Time
+}
+
+// Scorer rates how good a candidate is for the viewer, scores are between 0 and 1
+type Scorer interface {
+	Score(ctx ScoringContext) float64
+}
+
+// ScorerFunc adapts a plain function to the Scorer interface
+type ScorerFunc func(ctx ScoringContext) float64
+
+func (f ScorerFunc) Score(ctx ScoringContext) float64 {
+	return f(ctx)
+}
+
+// WeightedScorer is a scorer together with how much it counts towards the total score
+type WeightedScorer struct {
+	Scorer Scorer
+	Weight float64
+}
+
+// RankingWeights configures the weights of the built-in scorers, a zero weight disables a scorer
+type RankingWeights struct {
+	Recency         float64
+	Completeness    float64
+	InterestOverlap float64
+	Desirability    float64
+	Proximity       float64
+}
+
+var DefaultRankingWeights = RankingWeights{
+	Recency:         0.3,
+	Completeness:    0.2,
+	InterestOverlap: 0.3,
+	Desirability:    0.2,
+	Proximity:       0.2,
+}
+
+const (
+	// activityHalfLife is how long it takes for the recency score to halve
+	activityHalfLife = 3 * 24 * time.Hour
+
+	// desirabilityPriorSwipes pulls the like ratio of users with few received swipes towards
+	// desirabilityPriorRatio so a single like doesn't put a new user on top of every deck
+	desirabilityPriorSwipes = 10
+	desirabilityPriorRatio  = 0.5
+)
+
+// Ranker orders candidates by the weighted sum of its scorers
+type Ranker struct {
+	scorers []WeightedScorer
+}
+
+// NewRanker creates a ranker combining the given scorers
+func NewRanker(scorers ...WeightedScorer) *Ranker {
+	return &Ranker{scorers: scorers}
+}
+
+// NewRankerWithWeights creates a ranker from the built-in scorers
+func NewRankerWithWeights(weights RankingWeights) *Ranker {
+	return NewRanker(
+		WeightedScorer{Scorer: ScorerFunc(RecencyScore), Weight: weights.Recency},
+		WeightedScorer{Scorer: ScorerFunc(CompletenessScore), Weight: weights.Completeness},
+		WeightedScorer{Scorer: ScorerFunc(InterestOverlapScore), Weight: weights.InterestOverlap},
+		WeightedScorer{Scorer: ScorerFunc(DesirabilityScore), Weight: weights.Desirability},
+		WeightedScorer{Scorer: ScorerFunc(ProximityScore), Weight: weights.Proximity},
+	)
+}
+
+// Rank scores the candidates and sorts them best first, ties keep their original order
+func (r *Ranker) Rank(viewer *models.User, candidates []models.Candidate, activity map[int]models.SwipeActivity, now time.Time) {
+	for i := range candidates {
+		ctx := ScoringContext{Viewer: viewer, Candidate: &candidates[i].User, DistanceKm: candidates[i].DistanceKm,
+			Activity: activity[candidates[i].User.ID], Now: now}
+		candidates[i].Score = 0
+		for _, scorer := range r.scorers {
+			if scorer.Weight != 0 {
+				candidates[i].Score += scorer.Weight * scorer.Scorer.Score(ctx)
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
+
+// RecencyScore favours recently active users, it halves every activityHalfLife since the
+// candidate's last swipe, or since signing up for users who never swiped
+func RecencyScore(ctx ScoringContext) float64 {
+	lastActive := ctx.Activity.LastSwipeAt
+	if lastActive.IsZero() {
+		lastActive = ctx.Candidate.CreatedAt
+	}
+	if lastActive.IsZero() {
+		return 0
+	}
+	idle := ctx.Now.Sub(lastActive)
+	if idle <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(idle)/float64(activityHalfLife))
+}
+
+// CompletenessScore is the share of optional profile fields the candidate has filled in
+func CompletenessScore(ctx ScoringContext) float64 {
+	candidate := ctx.Candidate
+	fields := []bool{
+		strings.TrimSpace(candidate.Name) != "",
+		!candidate.Birthdate.IsZero(),
+		strings.TrimSpace(candidate.Bio) != "",
+		strings.TrimSpace(candidate.Occupation) != "",
+		len(candidate.Interests) > 0,
+	}
+	filled := 0
+	for _, ok := range fields {
+		if ok {
+			filled++
+		}
+	}
+	return float64(filled) / float64(len(fields))
+}
+
+// InterestOverlapScore is the Jaccard similarity of the viewer's and the candidate's interests
+func InterestOverlapScore(ctx ScoringContext) float64 {
+	viewerInterests := map[string]bool{}
+	for _, interest := range ctx.Viewer.Interests {
+		viewerInterests[interest] = true
+	}
+
+	union := len(viewerInterests)
+	shared := 0
+	seen := map[string]bool{}
+	for _, interest := range ctx.Candidate.Interests {
+		if seen[interest] {
+			continue
+		}
+		seen[interest] = true
+		if viewerInterests[interest] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// DesirabilityScore is the share of likes among the swipes the candidate received, smoothed
+// towards desirabilityPriorRatio while there are only a few of them
+func DesirabilityScore(ctx ScoringContext) float64 {
+	likes := float64(ctx.Activity.LikesReceived)
+	swipes := float64(ctx.Activity.SwipesReceived)
+	return (likes + desirabilityPriorRatio*desirabilityPriorSwipes) / (swipes + desirabilityPriorSwipes)
+}
+
+// ProximityScore favours nearby candidates, it falls from 1 next to the viewer to 0 at the
+// viewer's maximum distance. Viewers without a maximum distance don't rank by distance.
+func ProximityScore(ctx ScoringContext) float64 {
+	maxDistance := float64(ctx.Viewer.Preferences.MaxDistanceKm)
+	if ctx.DistanceKm == nil || maxDistance <= 0 {
+		return 0
+	}
+	return math.Max(0, 1-*ctx.DistanceKm/maxDistance)
+}
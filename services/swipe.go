@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
@@ -9,54 +10,293 @@ import (
 )
 
 type SwipeService interface {
-	RecordSwipe(swipe *models.Swipe) error
-	GetSwipeCandidates(userID int) ([]models.User, error)
+	RecordSwipe(swipe *models.Swipe) (*models.Match, error)
+	RewindLastSwipe(userID int) (*models.Swipe, error)
+	GetSwipeCandidates(userID int, cursor string, limit int) (*models.CandidatePage, error)
+	GetReceivedLikes(userID int) (*models.ReceivedLikes, error)
+	NotifyQuotaReset(day time.Time) (int, error)
 }
 
+const (
+	// DailySwipeLimit is how many likes and passes a user without unlimited swipes can make a day
+	DailySwipeLimit = 10
+	// DailySuperLikeLimit and PremiumDailySuperLikeLimit are the separate daily quotas of super likes
+	DailySuperLikeLimit        = 1
+	PremiumDailySuperLikeLimit = 5
+
+	// RewindWindow is how long after a swipe it can still be undone
+	RewindWindow = 5 * time.Minute
+)
+
+var (
+	ErrInvalidSwipeAction = errors.New("action must be like, pass or super_like")
+	ErrRewindNotAvailable = errors.New("rewind requires an active premium plan with rewind")
+	ErrNothingToRewind    = errors.New("there is no swipe from the last 5 minutes to rewind")
+	ErrRewindMatched      = errors.New("a swipe that made a match can't be rewound, unmatch instead")
+)
+
+const (
+	DefaultCandidatePageSize = 20
+	MaxCandidatePageSize     = 50
+
+	// CandidateRankWindow is how many profiles are ranked together. Decks are read this many
+	// profiles at a time and pages are cut from the ranked windows, so a page never needs the
+	// whole deck.
+	CandidateRankWindow = 100
+
+	// candidateScanBatch is how many users are read from the repository at a time while filling a window
+	candidateScanBatch = 100
+)
+
 type swipeService struct {
-	userRepo repositories.UserRepository
+	userRepo   repositories.UserRepository
+	matchRepo  repositories.MatchRepository
+	ranker     *Ranker
+	events     EventPublisher
+	swipeLocks keyedMutex
 }
 
-func NewSwipeService(userRepo repositories.UserRepository) SwipeService {
-	return &swipeService{userRepo}
+// NewSwipeService creates a swipe service ranking decks with the default weights
+func NewSwipeService(userRepo repositories.UserRepository, matchRepo repositories.MatchRepository) SwipeService {
+	return NewSwipeServiceWith(userRepo, matchRepo, NewRankerWithWeights(DefaultRankingWeights), discardEvents{})
 }
 
-func (s *swipeService) RecordSwipe(swipe *models.Swipe) error {
+// NewSwipeServiceWith creates a swipe service ranking decks with the given ranker and publishing
+// likes, matches and quota resets to events
+func NewSwipeServiceWith(userRepo repositories.UserRepository, matchRepo repositories.MatchRepository, ranker *Ranker, events EventPublisher) SwipeService {
+	return &swipeService{userRepo: userRepo, matchRepo: matchRepo, ranker: ranker, events: events}
+}
+
+// RecordSwipe stores the swipe and returns the new match when a like is reciprocated. Super likes
+// have their own daily quota and don't count towards the swipe limit.
+func (s *swipeService) RecordSwipe(swipe *models.Swipe) (*models.Match, error) {
+	switch swipe.Action {
+	case models.SwipeActionLike, models.SwipeActionPass, models.SwipeActionSuperLike:
+	default:
+		return nil, ErrInvalidSwipeAction
+	}
+
+	// Concurrent swipes of the same user must not both pass the daily limit check
+	unlock := s.swipeLocks.Lock(swipe.UserID)
+	defer unlock()
+
 	today := time.Now().UTC().Truncate(24 * time.Hour)
-	totalSwipes := 0
+	totalSwipes, totalSuperLikes := 0, 0
 
 	for _, s := range s.userRepo.GetSwipesForUser(swipe.UserID) {
 		if !s.CreatedAt.Before(today) { // Include swipes on or after "today"
-			totalSwipes++
+			if s.Action == models.SwipeActionSuperLike {
+				totalSuperLikes++
+			} else {
+				totalSwipes++
+			}
 			if s.TargetUserID == swipe.TargetUserID {
-				return errors.New("you have already swiped on this profile today")
+				return nil, errors.New("you have already swiped on this profile today")
 			}
 		}
 	}
 
 	user, err := s.userRepo.GetUserByID(swipe.UserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if user.PremiumExpiry != nil && user.PremiumExpiry.After(time.Now().UTC()) {
-		if user.PremiumFeatures.UnlimitedSwipes {
-			totalSwipes = 0
+	premium := hasActivePremium(user, time.Now())
+	if swipe.Action == models.SwipeActionSuperLike {
+		superLikeLimit := DailySuperLikeLimit
+		if premium {
+			superLikeLimit = PremiumDailySuperLikeLimit
 		}
+		if totalSuperLikes >= superLikeLimit {
+			return nil, errors.New("daily super like limit reached")
+		}
+	} else if !user.ActivePremiumFeatures(time.Now()).UnlimitedSwipes && totalSwipes >= DailySwipeLimit {
+		return nil, errors.New("daily swipe limit reached")
 	}
 
-	if totalSwipes >= 10 {
-		return errors.New("daily swipe limit reached")
-	}
 	swipe.CreatedAt = time.Now().UTC()
-	s.userRepo.SaveSwipe(swipe)
-	return nil
+	if err := s.userRepo.SaveSwipe(swipe); err != nil {
+		return nil, err
+	}
+
+	if !swipe.IsLike() {
+		return nil, nil
+	}
+	match, err := s.createMatchIfMutual(swipe)
+	if err != nil || match != nil {
+		return match, err
+	}
+
+	// Who liked is only revealed by the received likes, which may need premium
+	s.events.Publish(models.Event{
+		Type:      models.EventLikeReceived,
+		UserID:    swipe.TargetUserID,
+		Data:      map[string]interface{}{"super_like": swipe.Action == models.SwipeActionSuperLike},
+		CreatedAt: swipe.CreatedAt,
+	})
+	return nil, nil
 }
 
-// GetSwipeCandidates retrieves profiles that the user has not swiped on today
-func (s *swipeService) GetSwipeCandidates(userID int) ([]models.User, error) {
-	today := time.Now().Truncate(24 * time.Hour)
-	swipedUserIDs := map[int]bool{}
+// RewindLastSwipe undoes the user's most recent swipe if it was made within RewindWindow. The
+// swipe no longer counts towards the daily quotas and the profile is back in the deck.
+func (s *swipeService) RewindLastSwipe(userID int) (*models.Swipe, error) {
+	// Serialised with RecordSwipe, a swipe made at the same time is either rewound or left alone
+	unlock := s.swipeLocks.Lock(userID)
+	defer unlock()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !user.ActivePremiumFeatures(now).Rewind {
+		return nil, ErrRewindNotAvailable
+	}
+
+	var last *models.Swipe
+	swipes := s.userRepo.GetSwipesForUser(userID)
+	for i := range swipes {
+		if last == nil || swipes[i].ID > last.ID {
+			last = &swipes[i]
+		}
+	}
+	if last == nil || now.Sub(last.CreatedAt) > RewindWindow {
+		return nil, ErrNothingToRewind
+	}
+	if last.IsLike() {
+		if _, err := s.matchRepo.GetMatchBetween(userID, last.TargetUserID); err == nil {
+			return nil, ErrRewindMatched
+		}
+	}
+
+	if err := s.userRepo.DeleteSwipe(last.ID); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// GetReceivedLikes lists the likes the user hasn't answered yet, newest first. Who sent them is
+// only revealed to premium users, liking back is a regular swipe.
+func (s *swipeService) GetReceivedLikes(userID int) (*models.ReceivedLikes, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := s.pendingLikes(userID, s.userRepo.GetSwipesForUser(userID))
+	likes := make([]models.Swipe, 0, len(pending))
+	for _, like := range pending {
+		likes = append(likes, like)
+	}
+	sort.Slice(likes, func(i, j int) bool {
+		return likes[i].CreatedAt.After(likes[j].CreatedAt) || (likes[i].CreatedAt.Equal(likes[j].CreatedAt) && likes[i].ID > likes[j].ID)
+	})
+
+	received := &models.ReceivedLikes{Likes: []models.ReceivedLike{}, Visible: hasActivePremium(user, time.Now())}
+	for _, like := range likes {
+		liker, err := s.userRepo.GetUserByID(like.UserID)
+		if err != nil || liker.IsInactive {
+			continue
+		}
+		receivedLike := models.ReceivedLike{SuperLike: like.Action == models.SwipeActionSuperLike, LikedAt: like.CreatedAt}
+		if received.Visible {
+			receivedLike.User = liker
+		}
+		received.Likes = append(received.Likes, receivedLike)
+	}
+	return received, nil
+}
+
+// createMatchIfMutual creates a match when the target user has already liked the swiper back
+func (s *swipeService) createMatchIfMutual(swipe *models.Swipe) (*models.Match, error) {
+	if _, err := s.matchRepo.GetMatchBetween(swipe.UserID, swipe.TargetUserID); err == nil {
+		return nil, nil
+	}
+
+	// Likes made before the two users unmatched don't bring the match back
+	unmatchedAt := s.matchRepo.GetUnmatchesForUser(swipe.UserID)[swipe.TargetUserID]
+	likedBack := false
+	for _, targetSwipe := range s.userRepo.GetSwipesForUser(swipe.TargetUserID) {
+		if targetSwipe.TargetUserID == swipe.UserID && targetSwipe.IsLike() && targetSwipe.CreatedAt.After(unmatchedAt) {
+			likedBack = true
+			break
+		}
+	}
+	if !likedBack {
+		return nil, nil
+	}
+
+	match := &models.Match{
+		ID:        s.matchRepo.GenerateMatchID(),
+		UserID:    swipe.TargetUserID,
+		MatchedID: swipe.UserID,
+		CreatedAt: swipe.CreatedAt,
+	}
+	if err := s.matchRepo.SaveMatch(match); err != nil {
+		// The other user may have completed the match concurrently
+		if existing, getErr := s.matchRepo.GetMatchBetween(swipe.UserID, swipe.TargetUserID); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	for _, pair := range [][2]int{{match.UserID, match.MatchedID}, {match.MatchedID, match.UserID}} {
+		s.events.Publish(models.Event{
+			Type:      models.EventMutualLike,
+			UserID:    pair[0],
+			Data:      map[string]interface{}{"match_id": match.ID, "user_id": pair[1]},
+			CreatedAt: match.CreatedAt,
+		})
+	}
+	return match, nil
+}
+
+// NotifyQuotaReset tells the users who swiped the day before day that their daily quotas are
+// available again and returns how many users were notified
+func (s *swipeService) NotifyQuotaReset(day time.Time) (int, error) {
+	day = day.UTC().Truncate(24 * time.Hour)
+	previousDay := day.Add(-24 * time.Hour)
+
+	users := s.userRepo.GetAllUsers()
+	ids := make([]int, 0, len(users))
+	for _, user := range users {
+		if !user.IsInactive {
+			ids = append(ids, user.ID)
+		}
+	}
+
+	activities, err := s.userRepo.GetSwipeActivity(ids, day)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	for id, activity := range activities {
+		if activity.LastSwipeAt.Before(previousDay) {
+			continue
+		}
+		s.events.Publish(models.Event{Type: models.EventQuotaReset, UserID: id, CreatedAt: day})
+		notified++
+	}
+	return notified, nil
+}
+
+// GetSwipeCandidates retrieves a page of profiles that the user has not swiped on today. Profiles
+// that super liked the user come first, then boosted profiles, the rest of the deck is ordered
+// nearest first when there is a maximum distance and by user ID otherwise. Each segment is read
+// CandidateRankWindow profiles at a time and every window is ranked best first, equal scores
+// keeping the deck order, before the pages are cut from it.
+func (s *swipeService) GetSwipeCandidates(userID int, cursor string, limit int) (*models.CandidatePage, error) {
+	position, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultCandidatePageSize
+	}
+	if limit > MaxCandidatePageSize {
+		limit = MaxCandidatePageSize
+	}
 
 	// Validate user existence first
 	currentUser, err := s.userRepo.GetUserByID(userID)
@@ -65,28 +305,242 @@ func (s *swipeService) GetSwipeCandidates(userID int) ([]models.User, error) {
 	}
 
 	// Collect user IDs that have already been swiped on today
-	for _, swipe := range s.userRepo.GetSwipesForUser(userID) {
+	now := time.Now()
+	today := now.Truncate(24 * time.Hour)
+	swipes := s.userRepo.GetSwipesForUser(userID)
+	swipedUserIDs := map[int]bool{}
+	for _, swipe := range swipes {
 		if swipe.CreatedAt.After(today) {
 			swipedUserIDs[swipe.TargetUserID] = true
 		}
 	}
 
-	// Determine the opposite gender
-	oppositeGender := "male"
-	if currentUser.Gender == "male" {
-		oppositeGender = "female"
+	// Keep unswiped profiles that are compatible both ways
+	eligible := func(candidate models.Candidate) bool {
+		user := &candidate.User
+		return user.ID != userID && !swipedUserIDs[user.ID] && !user.IsInactive &&
+			isMutuallyCompatible(currentUser, user, candidate.DistanceKm, now)
+	}
+
+	// Profiles of a segment are left out of the ones after it, they were already shown there
+	superLikers := s.pendingSuperLikers(userID, swipes)
+	prioritised := map[int]bool{}
+	for _, user := range superLikers {
+		prioritised[user.ID] = true
+	}
+	segments := []deckSegment{
+		segmentSuperLiked: loadedSegment(currentUser, superLikers, false, eligible),
+		segmentBoosted: loadedSegment(currentUser, s.userRepo.GetBoostedUsers(now), false, func(candidate models.Candidate) bool {
+			return !prioritised[candidate.User.ID] && eligible(candidate)
+		}),
+	}
+	regularEligible := func(candidate models.Candidate) bool {
+		return !prioritised[candidate.User.ID] && !candidate.User.IsBoosted(now) && eligible(candidate)
+	}
+	if currentUser.Location != nil && currentUser.Preferences.MaxDistanceKm > 0 {
+		// Only the users around the current user are looked at thanks to the repository's spatial index
+		nearby := s.userRepo.GetUsersNear(*currentUser.Location, float64(currentUser.Preferences.MaxDistanceKm))
+		segments = append(segments, loadedSegment(currentUser, nearby, true, regularEligible))
+	} else {
+		segments = append(segments, s.scannedSegment(currentUser, regularEligible))
+	}
+
+	// Later pages rank with the time and the activity of when the first page was ranked
+	rankedAt := time.UnixMilli(now.UnixMilli())
+	first := segmentSuperLiked
+	if cursor != "" {
+		rankedAt = time.UnixMilli(position.RankedAt)
+		first = position.Segment
+	}
+	rank := func(window []models.Candidate) error {
+		ids := make([]int, len(window))
+		for i, candidate := range window {
+			ids[i] = candidate.User.ID
+		}
+		activity, err := s.userRepo.GetSwipeActivity(ids, rankedAt)
+		if err != nil {
+			return err
+		}
+		s.ranker.Rank(currentUser, window, activity, rankedAt)
+		return nil
+	}
+
+	page := &models.CandidatePage{Candidates: []models.Candidate{}}
+	var last deckCursor
+	for segment := first; segment <= segmentRegular; segment++ {
+		deck := segments[segment]
+
+		// The cursor's window keeps its bounds, profiles swiped on since simply drop out of it
+		after, through, resume := deckPosition{}, (*deckPosition)(nil), false
+		if cursor != "" && segment == position.Segment {
+			after, through, resume = position.After, &position.Through, true
+		}
+		for {
+			window, end := deck.window(after, through)
+			if through != nil {
+				end = *through
+			}
+			if len(window) == 0 {
+				if through == nil {
+					break
+				}
+				after, through, resume = end, nil, false
+				continue
+			}
+			if err := rank(window); err != nil {
+				return nil, err
+			}
+
+			for _, candidate := range window {
+				current := deck.position(candidate)
+				if resume && !position.after(candidate.Score, current) {
+					continue
+				}
+				if len(page.Candidates) == limit {
+					page.NextCursor = encodeCursor(last)
+					return page, nil
+				}
+				candidate.SuperLikedYou = segment == segmentSuperLiked
+				page.Candidates = append(page.Candidates, candidate)
+				last = deckCursor{Segment: segment, After: after, Through: end, Score: candidate.Score,
+					Last: current, RankedAt: rankedAt.UnixMilli()}
+			}
+			after, through, resume = end, nil, false
+		}
+	}
+	return page, nil
+}
+
+// pendingLikes returns the latest like of every user who liked the user since the user last
+// swiped on them, keyed by the liker. Users that were already liked back are matched instead,
+// likes made before the two users unmatched no longer count. swipes are the swipes made by the user.
+func (s *swipeService) pendingLikes(userID int, swipes []models.Swipe) map[int]models.Swipe {
+	unmatchedAt := s.matchRepo.GetUnmatchesForUser(userID)
+	answeredAt := map[int]time.Time{}
+	liked := map[int]bool{}
+	for _, swipe := range swipes {
+		if swipe.CreatedAt.After(answeredAt[swipe.TargetUserID]) {
+			answeredAt[swipe.TargetUserID] = swipe.CreatedAt
+		}
+		if swipe.IsLike() && swipe.CreatedAt.After(unmatchedAt[swipe.TargetUserID]) {
+			liked[swipe.TargetUserID] = true
+		}
+	}
+
+	// Received swipes come oldest first, a later like replaces an earlier one
+	pending := map[int]models.Swipe{}
+	for _, received := range s.userRepo.GetSwipesReceived(userID) {
+		if received.IsLike() && !liked[received.UserID] && answeredAt[received.UserID].Before(received.CreatedAt) &&
+			received.CreatedAt.After(unmatchedAt[received.UserID]) {
+			pending[received.UserID] = received
+		}
+	}
+	return pending
+}
+
+// pendingSuperLikers returns the users, ordered by ID, whose pending like is a super like
+func (s *swipeService) pendingSuperLikers(userID int, swipes []models.Swipe) []*models.User {
+	var ids []int
+	for id, like := range s.pendingLikes(userID, swipes) {
+		if like.Action == models.SwipeActionSuperLike {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range ids {
+		if user, err := s.userRepo.GetUserByID(id); err == nil {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// deckSegment reads a segment of a deck in the order its windows are cut in
+type deckSegment struct {
+	// read returns the next profiles after the position, none once the segment is exhausted
+	read       func(after deckPosition) []models.Candidate
+	byDistance bool
+	eligible   func(candidate models.Candidate) bool
+}
+
+// position tells where a profile sits in the segment
+func (d deckSegment) position(candidate models.Candidate) deckPosition {
+	if d.byDistance {
+		return deckPosition{DistanceKm: candidate.DistanceKm, ID: candidate.User.ID}
 	}
+	return deckPosition{ID: candidate.User.ID}
+}
 
-	// Retrieve all users and filter by opposite gender and unswiped profiles
-	candidates := []models.User{}
-	for _, user := range s.userRepo.GetAllUsers() {
-		if user.ID != userID &&
-			user.Gender == oppositeGender &&
-			!swipedUserIDs[user.ID] &&
-			!user.IsInactive {
-			candidates = append(candidates, *user)
+// window returns the eligible profiles after the position, up to through when it is set and
+// CandidateRankWindow of them otherwise, and the position of the last profile it read
+func (d deckSegment) window(after deckPosition, through *deckPosition) ([]models.Candidate, deckPosition) {
+	var window []models.Candidate
+	for {
+		candidates := d.read(after)
+		if len(candidates) == 0 {
+			return window, after
+		}
+		for _, candidate := range candidates {
+			position := d.position(candidate)
+			if through != nil && through.before(position) {
+				return window, after
+			}
+			after = position
+			if d.eligible(candidate) {
+				window = append(window, candidate)
+				if through == nil && len(window) == CandidateRankWindow {
+					return window, after
+				}
+			}
 		}
 	}
+}
 
-	return candidates, nil
+// loadedSegment makes a segment of users that are already loaded, ordered by distance when
+// byDistance is set and by ID otherwise
+func loadedSegment(viewer *models.User, users []*models.User, byDistance bool,
+	eligible func(models.Candidate) bool) deckSegment {
+	segment := deckSegment{byDistance: byDistance, eligible: eligible}
+	candidates := make([]models.Candidate, 0, len(users))
+	for _, user := range users {
+		candidates = append(candidates, models.Candidate{User: *user, DistanceKm: distanceBetween(viewer, user)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return segment.position(candidates[i]).before(segment.position(candidates[j]))
+	})
+
+	segment.read = func(after deckPosition) []models.Candidate {
+		start := sort.Search(len(candidates), func(i int) bool { return after.before(segment.position(candidates[i])) })
+		return candidates[start:]
+	}
+	return segment
+}
+
+// scannedSegment makes a segment of every user, read in ID order from the repository a batch
+// at a time
+func (s *swipeService) scannedSegment(viewer *models.User, eligible func(models.Candidate) bool) deckSegment {
+	// A short batch is the end of the users, reading past it needs no query
+	lastID := -1
+	return deckSegment{
+		eligible: eligible,
+		read: func(after deckPosition) []models.Candidate {
+			if lastID >= 0 && after.ID >= lastID {
+				return nil
+			}
+			users := s.userRepo.GetUsersAfter(after.ID, candidateScanBatch)
+			if len(users) < candidateScanBatch {
+				lastID = after.ID
+				if len(users) > 0 {
+					lastID = users[len(users)-1].ID
+				}
+			}
+			candidates := make([]models.Candidate, 0, len(users))
+			for _, user := range users {
+				candidates = append(candidates, models.Candidate{User: *user, DistanceKm: distanceBetween(viewer, user)})
+			}
+			return candidates
+		},
+	}
 }
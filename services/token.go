@@ -0,0 +1,107 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+)
+
+// RefreshTokenTTL is how long a refresh token can be used to obtain new access tokens
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+type TokenService interface {
+	IssueTokens(userID int) (*models.TokenPair, error)
+	RefreshTokens(refreshToken string) (*models.TokenPair, error)
+	Logout(userID int, claims *utils.TokenClaims, refreshToken string) error
+	IsAccessTokenRevoked(jti string) bool
+}
+
+type tokenService struct {
+	tokenRepo repositories.TokenRepository
+}
+
+func NewTokenService(tokenRepo repositories.TokenRepository) TokenService {
+	return &tokenService{tokenRepo}
+}
+
+// IssueTokens creates an access token and a new refresh token for the user
+func (s *tokenService) IssueTokens(userID int) (*models.TokenPair, error) {
+	accessToken, err := utils.GenerateJWT(userID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	now := time.Now()
+	err = s.tokenRepo.SaveRefreshToken(&models.RefreshToken{
+		TokenHash: hashToken(refreshToken),
+		UserID:    userID,
+		ExpiresAt: now.Add(RefreshTokenTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// RefreshTokens exchanges a valid refresh token for a new pair, the used refresh token is revoked (rotation)
+func (s *tokenService) RefreshTokens(refreshToken string) (*models.TokenPair, error) {
+	// Revoking is the check, a token used twice concurrently only passes it once
+	stored, err := s.tokenRepo.RevokeRefreshToken(hashToken(refreshToken), time.Now())
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	return s.IssueTokens(stored.UserID)
+}
+
+// Logout revokes the access token of the current request and, when given, the user's refresh token
+func (s *tokenService) Logout(userID int, claims *utils.TokenClaims, refreshToken string) error {
+	if claims != nil && claims.ID != "" {
+		if err := s.tokenRepo.RevokeAccessToken(claims.ID, claims.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.tokenRepo.GetRefreshToken(hashToken(refreshToken))
+	if err != nil || stored.UserID != userID {
+		return errors.New("invalid refresh token")
+	}
+	if stored.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	stored.RevokedAt = &now
+	return s.tokenRepo.UpdateRefreshToken(stored)
+}
+
+// IsAccessTokenRevoked checks whether the access token with the given jti has been revoked
+func (s *tokenService) IsAccessTokenRevoked(jti string) bool {
+	return s.tokenRepo.IsAccessTokenRevoked(jti)
+}
+
+// hashToken hashes opaque tokens before they are stored, a leaked store can't be replayed
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
@@ -12,25 +13,69 @@ import (
 
 type UserService interface {
 	SignUp(user *models.User) error
-	Login(creds models.Credentials) (string, error)
+	Login(creds models.Credentials) (*models.TokenPair, error)
+	GetPlans() []models.Plan
+	PurchasePlan(userID int, planID string) (*models.Plan, error)
 	EnablePremiumFeature(userID int, duration int, features []string) error
+	ExpirePremiumFeatures(now time.Time) (int, error)
+	NotifyExpiringPremium(now time.Time) (int, error)
+	GetProfile(userID int) (*models.User, error)
+	UpdateProfile(userID int, update models.ProfileUpdate) (*models.User, error)
+	UpdateLocation(userID int, location models.Location) (*models.User, error)
+	BoostProfile(userID int) (*time.Time, error)
+	SetVerified(userID int, verified bool) error
 }
 
+const (
+	// BoostDuration is how long a boost keeps a profile at the top of other users' decks
+	BoostDuration = 30 * time.Minute
+	// BoostCooldown is the time between the start of two boosts of the same user
+	BoostCooldown = 24 * time.Hour
+
+	// PremiumExpiringNotice is how long before the premium expiry users are warned about it
+	PremiumExpiringNotice = 24 * time.Hour
+)
+
+var (
+	ErrEmailAlreadyExists = repositories.ErrEmailAlreadyExists
+	ErrPhoneAlreadyExists = repositories.ErrPhoneAlreadyExists
+	ErrBoostNotAvailable  = errors.New("profile boost requires an active premium plan with profile boost")
+	ErrBoostActive        = errors.New("profile boost is already running")
+	ErrBoostCoolingDown   = errors.New("profile boost can only be used once every 24 hours")
+)
+
 type userService struct {
-	userRepo repositories.UserRepository
+	userRepo         repositories.UserRepository
+	planRepo         repositories.PlanRepository
+	premiumEventRepo repositories.PremiumEventRepository
+	tokenService     TokenService
+	events           EventPublisher
+	userLocks        keyedMutex
 }
 
-func NewUserService(userRepo repositories.UserRepository) UserService {
-	return &userService{userRepo}
+func NewUserService(userRepo repositories.UserRepository, planRepo repositories.PlanRepository, premiumEventRepo repositories.PremiumEventRepository, tokenService TokenService) UserService {
+	return NewUserServiceWith(userRepo, planRepo, premiumEventRepo, tokenService, discardEvents{})
+}
+
+// NewUserServiceWith creates a user service publishing premium changes to events
+func NewUserServiceWith(userRepo repositories.UserRepository, planRepo repositories.PlanRepository, premiumEventRepo repositories.PremiumEventRepository, tokenService TokenService, events EventPublisher) UserService {
+	return &userService{userRepo: userRepo, planRepo: planRepo, premiumEventRepo: premiumEventRepo, tokenService: tokenService, events: events}
 }
 
 func (s *userService) SignUp(user *models.User) error {
+	if err := validateBirthdate(user.Birthdate, time.Now()); err != nil {
+		return err
+	}
+	if err := validateSignUpProfile(user); err != nil {
+		return err
+	}
+
 	if _, err := s.userRepo.GetUserByEmail(user.Email); err == nil {
-		return errors.New("email already exists")
+		return ErrEmailAlreadyExists
 	}
 
 	if _, err := s.userRepo.GetUserByPhone(user.Phone); err == nil {
-		return errors.New("phone number already exists")
+		return ErrPhoneAlreadyExists
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
@@ -40,6 +85,12 @@ func (s *userService) SignUp(user *models.User) error {
 
 	user.ID = s.userRepo.GenerateUserID()
 	user.Password = string(hashedPassword)
+	user.Bio = strings.TrimSpace(user.Bio)
+	user.Occupation = strings.TrimSpace(user.Occupation)
+	user.Interests = normalizeInterests(user.Interests)
+	user.BoostedUntil = nil  // only BoostProfile opens a boost window
+	user.PremiumExpiry = nil // premium is only granted by a paid purchase
+	user.PremiumFeatures = models.PremiumFeatures{}
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
@@ -49,7 +100,7 @@ func (s *userService) SignUp(user *models.User) error {
 	return nil
 }
 
-func (s *userService) Login(creds models.Credentials) (string, error) {
+func (s *userService) Login(creds models.Credentials) (*models.TokenPair, error) {
 	var user *models.User
 	var err error
 
@@ -60,27 +111,44 @@ func (s *userService) Login(creds models.Credentials) (string, error) {
 			user, err = s.userRepo.GetUserByPhone(creds.Identifier)
 		}
 		if err != nil {
-			return "", errors.New("invalid email/phone or password")
+			return nil, errors.New("invalid email/phone or password")
 		}
 	} else {
-		return "", errors.New("identifier is required")
+		return nil, errors.New("identifier is required")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(creds.Password))
 	if err != nil {
-		return "", errors.New("invalid email/phone or password")
+		return nil, errors.New("invalid email/phone or password")
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	return s.tokenService.IssueTokens(user.ID)
+}
+
+// GetPlans lists the premium plans that can be purchased
+func (s *userService) GetPlans() []models.Plan {
+	return s.planRepo.GetAllPlans()
+}
+
+// PurchasePlan resolves a plan from the catalogue and activates its features for the plan's duration
+func (s *userService) PurchasePlan(userID int, planID string) (*models.Plan, error) {
+	plan, err := s.planRepo.GetPlanByID(planID)
 	if err != nil {
-		return "", errors.New("failed to generate token")
+		return nil, err
 	}
 
-	return token, nil
+	if err := s.EnablePremiumFeature(userID, plan.DurationDays, plan.Features); err != nil {
+		return nil, err
+	}
+	return plan, nil
 }
 
-// PurchasePremium activates a premium feature for a user
+// EnablePremiumFeature activates premium features for a user
 func (s *userService) EnablePremiumFeature(userID int, duration int, features []string) error {
+	// Serialise writes per user so concurrent requests can't overwrite each other's changes
+	unlock := s.userLocks.Lock(userID)
+	defer unlock()
+
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
 		return err
@@ -90,33 +158,317 @@ func (s *userService) EnablePremiumFeature(userID int, duration int, features []
 		return errors.New("user account is inactive")
 	}
 
-	// Check if PremiumExpiry is in the future; extend or set it
-	newExpiry := time.Now().Add(time.Duration(duration) * 24 * time.Hour)
-	if user.PremiumExpiry != nil && user.PremiumExpiry.After(time.Now()) {
-		newExpiry = user.PremiumExpiry.Add(time.Duration(duration) * 24 * time.Hour)
+	// Each feature of the plan runs for the plan's duration, a feature that is still running is
+	// extended from its own expiry. Features the plan doesn't include keep their expiry.
+	now := time.Now()
+	expiry := user.FeatureExpiry()
+	extend := func(until *time.Time) *time.Time {
+		start := now
+		if until != nil && until.After(now) {
+			start = *until
+		}
+		end := start.Add(time.Duration(duration) * 24 * time.Hour)
+		return &end
 	}
-	user.PremiumExpiry = &newExpiry
-
-	// Enable the specified features
 	for _, feature := range features {
 		switch feature {
 		case "UnlimitedSwipes":
-			if user.PremiumFeatures.UnlimitedSwipes {
-				return errors.New("unlimited swipes is already active")
-			}
-			user.PremiumFeatures.UnlimitedSwipes = true
-		case "IsVerified":
-			if user.PremiumFeatures.IsVerified {
-				return errors.New("user is already verified")
-			}
-			user.PremiumFeatures.IsVerified = true
+			expiry.UnlimitedSwipes = extend(expiry.UnlimitedSwipes)
+		case "ProfileBoost", "IsVerified": // IsVerified is the old name of ProfileBoost
+			expiry.ProfileBoost = extend(expiry.ProfileBoost)
+		case "Rewind":
+			expiry.Rewind = extend(expiry.Rewind)
 		default:
 			return errors.New("invalid premium feature: " + feature)
 		}
 	}
 
-	// Update user and persist changes
-	user.UpdatedAt = time.Now()
-	s.userRepo.UpdateUser(user)
+	// Features that lapsed are switched off even if the expiry job hasn't cleared them yet
+	user.PremiumFeatureExpiry = expiry
+	user.PremiumFeatures = user.ActivePremiumFeatures(now)
+	user.PremiumExpiry = latestExpiry(expiry)
+
+	// Update user and persist changes, nothing is announced unless the features were saved
+	user.UpdatedAt = now
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+
+	s.events.Publish(models.Event{
+		Type:   models.EventPremiumActivated,
+		UserID: user.ID,
+		Data: map[string]interface{}{"premium_expiry": user.PremiumExpiry, "premium_features": user.PremiumFeatures,
+			"premium_feature_expiry": user.PremiumFeatureExpiry},
+		CreatedAt: user.UpdatedAt,
+	})
 	return nil
 }
+
+// ExpirePremiumFeatures clears every premium feature that expired before now, records an expiry
+// event with the lapsed features of each user and returns how many users had features expire
+func (s *userService) ExpirePremiumFeatures(now time.Time) (int, error) {
+	expired := 0
+	for _, candidate := range s.userRepo.GetAllUsers() {
+		if len(lapsedPremiumFeatures(candidate, now)) == 0 {
+			continue
+		}
+
+		ok, err := s.expireUserPremium(candidate.ID, now)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (s *userService) expireUserPremium(userID int, now time.Time) (bool, error) {
+	unlock := s.userLocks.Lock(userID)
+	defer unlock()
+
+	// Reload under the lock, the user may have renewed in the meantime
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+	features := lapsedPremiumFeatures(user, now)
+	if len(features) == 0 {
+		return false, nil
+	}
+
+	user.PremiumFeatures = user.ActivePremiumFeatures(now)
+	user.UpdatedAt = now
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return false, err
+	}
+
+	err = s.premiumEventRepo.SavePremiumEvent(&models.PremiumEvent{
+		UserID:    user.ID,
+		Type:      models.PremiumEventExpired,
+		Features:  features,
+		CreatedAt: now,
+	})
+	s.events.Publish(models.Event{
+		Type:      models.EventPremiumExpired,
+		UserID:    user.ID,
+		Data:      map[string]interface{}{"features": features},
+		CreatedAt: now,
+	})
+	return true, err
+}
+
+// NotifyExpiringPremium warns every user whose premium expires within PremiumExpiringNotice of
+// now, once per expiry, and returns how many users were warned
+func (s *userService) NotifyExpiringPremium(now time.Time) (int, error) {
+	warned := 0
+	for _, user := range s.userRepo.GetAllUsers() {
+		if !hasActivePremium(user, now) || user.PremiumExpiry.Sub(now) > PremiumExpiringNotice {
+			continue
+		}
+
+		alreadyWarned := false
+		for _, event := range s.premiumEventRepo.GetPremiumEventsForUser(user.ID) {
+			if event.Type == models.PremiumEventExpiring && event.ExpiresAt != nil && event.ExpiresAt.Equal(*user.PremiumExpiry) {
+				alreadyWarned = true
+				break
+			}
+		}
+		if alreadyWarned {
+			continue
+		}
+
+		features := premiumFeatureNames(user.PremiumFeatures)
+		if err := s.premiumEventRepo.SavePremiumEvent(&models.PremiumEvent{
+			UserID:    user.ID,
+			Type:      models.PremiumEventExpiring,
+			Features:  features,
+			ExpiresAt: user.PremiumExpiry,
+			CreatedAt: now,
+		}); err != nil {
+			return warned, err
+		}
+		s.events.Publish(models.Event{
+			Type:      models.EventPremiumExpiring,
+			UserID:    user.ID,
+			Data:      map[string]interface{}{"premium_expiry": user.PremiumExpiry, "features": features},
+			CreatedAt: now,
+		})
+		warned++
+	}
+	return warned, nil
+}
+
+// GetProfile retrieves the user's own profile, the password hash is never returned
+func (s *userService) GetProfile(userID int) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// UpdateProfile applies the fields set in update to the user's profile
+func (s *userService) UpdateProfile(userID int, update models.ProfileUpdate) (*models.User, error) {
+	if err := validateProfileUpdate(update); err != nil {
+		return nil, err
+	}
+
+	unlock := s.userLocks.Lock(userID)
+	defer unlock()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Phone != nil && *update.Phone != user.Phone {
+		if existing, err := s.userRepo.GetUserByPhone(*update.Phone); err == nil && existing.ID != userID {
+			return nil, ErrPhoneAlreadyExists
+		}
+		user.Phone = *update.Phone
+	}
+	if update.Name != nil {
+		user.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Gender != nil {
+		user.Gender = *update.Gender
+	}
+	if update.Bio != nil {
+		user.Bio = strings.TrimSpace(*update.Bio)
+	}
+	if update.Occupation != nil {
+		user.Occupation = strings.TrimSpace(*update.Occupation)
+	}
+	if update.Interests != nil {
+		user.Interests = normalizeInterests(*update.Interests)
+	}
+	if update.Preferences != nil {
+		user.Preferences = *update.Preferences
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// UpdateLocation stores the user's current location
+func (s *userService) UpdateLocation(userID int, location models.Location) (*models.User, error) {
+	if err := validateLocation(location); err != nil {
+		return nil, err
+	}
+
+	unlock := s.userLocks.Lock(userID)
+	defer unlock()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Location = &location
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// BoostProfile puts the user at the top of other users' decks for BoostDuration and returns
+// when the boost ends
+func (s *userService) BoostProfile(userID int) (*time.Time, error) {
+	unlock := s.userLocks.Lock(userID)
+	defer unlock()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if user.IsInactive {
+		return nil, errors.New("user account is inactive")
+	}
+	if !user.ActivePremiumFeatures(now).ProfileBoost {
+		return nil, ErrBoostNotAvailable
+	}
+	if user.IsBoosted(now) {
+		return nil, ErrBoostActive
+	}
+	if user.BoostedUntil != nil && now.Before(user.BoostedUntil.Add(-BoostDuration).Add(BoostCooldown)) {
+		return nil, ErrBoostCoolingDown
+	}
+
+	boostedUntil := now.Add(BoostDuration)
+	user.BoostedUntil = &boostedUntil
+	user.UpdatedAt = now
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+	return &boostedUntil, nil
+}
+
+// SetVerified sets or clears the user's verified badge, it is only called once a
+// verification request has been decided
+func (s *userService) SetVerified(userID int, verified bool) error {
+	unlock := s.userLocks.Lock(userID)
+	defer unlock()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	user.IsVerified = verified
+	user.UpdatedAt = time.Now()
+	return s.userRepo.UpdateUser(user)
+}
+
+// premiumFeatureNames lists the enabled features by their plan names
+func premiumFeatureNames(features models.PremiumFeatures) []string {
+	var names []string
+	if features.UnlimitedSwipes {
+		names = append(names, "UnlimitedSwipes")
+	}
+	if features.ProfileBoost {
+		names = append(names, "ProfileBoost")
+	}
+	if features.Rewind {
+		names = append(names, "Rewind")
+	}
+	return names
+}
+
+// lapsedPremiumFeatures lists the features that are still switched on but expired at now
+func lapsedPremiumFeatures(user *models.User, now time.Time) []string {
+	active := user.ActivePremiumFeatures(now)
+	return premiumFeatureNames(models.PremiumFeatures{
+		UnlimitedSwipes: user.PremiumFeatures.UnlimitedSwipes && !active.UnlimitedSwipes,
+		ProfileBoost:    user.PremiumFeatures.ProfileBoost && !active.ProfileBoost,
+		Rewind:          user.PremiumFeatures.Rewind && !active.Rewind,
+	})
+}
+
+// latestExpiry returns when the last of the premium features runs out, nil if none was bought
+func latestExpiry(expiry models.PremiumFeatureExpiry) *time.Time {
+	var latest *time.Time
+	for _, until := range []*time.Time{expiry.UnlimitedSwipes, expiry.ProfileBoost, expiry.Rewind} {
+		if until != nil && (latest == nil || until.After(*latest)) {
+			latest = until
+		}
+	}
+	return latest
+}
+
+// hasActivePremium reports whether the user's premium subscription is still running at the given time
+func hasActivePremium(user *models.User, now time.Time) bool {
+	return user.PremiumExpiry != nil && user.PremiumExpiry.After(now)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/verification"
+)
+
+// MaxVerificationDocumentSize caps the size of an uploaded selfie or document
+const MaxVerificationDocumentSize = 5 << 20
+
+// verificationContentTypes are the accepted uploads, the type is sniffed from the content
+var verificationContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+var (
+	ErrAlreadyVerified      = errors.New("user is already verified")
+	ErrVerificationPending  = errors.New("a verification request is already pending")
+	ErrVerificationReviewed = errors.New("verification request has already been reviewed")
+	ErrVerificationNotFound = errors.New("verification request not found")
+)
+
+type VerificationService interface {
+	SubmitVerification(userID int, documentType string, document []byte) (*models.VerificationRequest, error)
+	GetVerificationStatus(userID int) (*models.VerificationRequest, error)
+	GetPendingVerifications() []models.PendingVerification
+	GetVerification(verificationID int) (*models.VerificationRequest, error)
+	ReviewVerification(verificationID int, decision string, reason string) (*models.VerificationRequest, error)
+}
+
+type verificationService struct {
+	userRepo          repositories.UserRepository
+	verificationRepo  repositories.VerificationRepository
+	checker           verification.Checker
+	userService       UserService
+	verificationLocks keyedMutex
+}
+
+func NewVerificationService(userRepo repositories.UserRepository, verificationRepo repositories.VerificationRepository, checker verification.Checker, userService UserService) VerificationService {
+	return &verificationService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		checker:          checker,
+		userService:      userService,
+	}
+}
+
+// SubmitVerification queues a selfie or identity document for review and lets the automated
+// checker decide it straight away if it can
+func (s *verificationService) SubmitVerification(userID int, documentType string, document []byte) (*models.VerificationRequest, error) {
+	if documentType != models.VerificationDocumentSelfie && documentType != models.VerificationDocumentID {
+		return nil, errors.New("document_type must be selfie or id_document")
+	}
+	if len(document) > MaxVerificationDocumentSize {
+		return nil, errors.New("document must not be larger than 5 MB")
+	}
+	contentType := http.DetectContentType(document)
+	if len(document) == 0 || !verificationContentTypes[contentType] {
+		return nil, errors.New("document must be a JPEG, PNG or PDF file")
+	}
+
+	// One pending request per user, even when the same upload is sent twice at once
+	unlock := s.verificationLocks.Lock(userID)
+	defer unlock()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsInactive {
+		return nil, errors.New("user account is inactive")
+	}
+	if user.IsVerified {
+		return nil, ErrAlreadyVerified
+	}
+	if latest, err := s.verificationRepo.GetLatestVerificationForUser(userID); err == nil && latest.Status == models.VerificationStatusPending {
+		return nil, ErrVerificationPending
+	}
+
+	request := &models.VerificationRequest{
+		ID:           s.verificationRepo.GenerateVerificationID(),
+		UserID:       userID,
+		DocumentType: documentType,
+		ContentType:  contentType,
+		Document:     document,
+		Status:       models.VerificationStatusPending,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.verificationRepo.SaveVerification(request); err != nil {
+		return nil, err
+	}
+
+	// A failing checker is not the user's fault, the request simply waits for an administrator
+	decision, err := s.checker.Check(*request)
+	if err != nil || decision.Status == models.VerificationStatusPending {
+		return request, nil
+	}
+	if err := s.resolve(request, decision.Status, decision.Reason, s.checker.Name()); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetVerificationStatus retrieves the user's most recent verification request
+func (s *verificationService) GetVerificationStatus(userID int) (*models.VerificationRequest, error) {
+	request, err := s.verificationRepo.GetLatestVerificationForUser(userID)
+	if err != nil {
+		return nil, ErrVerificationNotFound
+	}
+	return request, nil
+}
+
+// GetPendingVerifications lists the review queue, oldest request first, with the users who sent
+// the requests
+func (s *verificationService) GetPendingVerifications() []models.PendingVerification {
+	requests := s.verificationRepo.GetPendingVerifications()
+	pending := make([]models.PendingVerification, 0, len(requests))
+	for _, request := range requests {
+		entry := models.PendingVerification{Request: request}
+		if user, err := s.userRepo.GetUserByID(request.UserID); err == nil {
+			entry.User = user
+		}
+		pending = append(pending, entry)
+	}
+	return pending
+}
+
+// GetVerification retrieves a verification request with its document for review
+func (s *verificationService) GetVerification(verificationID int) (*models.VerificationRequest, error) {
+	request, err := s.verificationRepo.GetVerificationByID(verificationID)
+	if err != nil {
+		return nil, ErrVerificationNotFound
+	}
+	return request, nil
+}
+
+// ReviewVerification records an administrator's decision, approve or reject, on a pending request
+func (s *verificationService) ReviewVerification(verificationID int, decision string, reason string) (*models.VerificationRequest, error) {
+	var status string
+	switch decision {
+	case "approve":
+		status = models.VerificationStatusApproved
+	case "reject":
+		if reason == "" {
+			return nil, errors.New("a reason is required to reject a verification request")
+		}
+		status = models.VerificationStatusRejected
+	default:
+		return nil, errors.New("decision must be approve or reject")
+	}
+
+	request, err := s.verificationRepo.GetVerificationByID(verificationID)
+	if err != nil {
+		return nil, ErrVerificationNotFound
+	}
+
+	unlock := s.verificationLocks.Lock(request.UserID)
+	defer unlock()
+
+	// Reload under the lock, another reviewer may have been quicker
+	if request, err = s.verificationRepo.GetVerificationByID(verificationID); err != nil {
+		return nil, ErrVerificationNotFound
+	}
+	if request.Status != models.VerificationStatusPending {
+		return nil, ErrVerificationReviewed
+	}
+
+	if err := s.resolve(request, status, reason, models.VerificationReviewerAdmin); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// resolve stores the decision on the request, approval sets the user's verified badge first so
+// a request is never approved without it
+func (s *verificationService) resolve(request *models.VerificationRequest, status string, reason string, reviewer string) error {
+	if status != models.VerificationStatusApproved && status != models.VerificationStatusRejected {
+		return errors.New("invalid verification status: " + status)
+	}
+	if status == models.VerificationStatusApproved {
+		if err := s.userService.SetVerified(request.UserID, true); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.Reason = reason
+	request.ReviewedBy = reviewer
+	request.ReviewedAt = &now
+	return s.verificationRepo.UpdateVerification(request)
+}
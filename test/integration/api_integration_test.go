@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/routes"
@@ -37,9 +38,39 @@ func TestAPIIntegration(t *testing.T) {
 			name:               "SignUp - Success",
 			method:             "POST",
 			url:                "/signup",
-			body:               map[string]string{"email": "newuser@example.com", "password": "newpassword", "phone": "1112223333", "name": "New User", "gender": "male"},
+			body:               map[string]string{"email": "newuser@example.com", "password": "newpassword", "phone": "1112223333", "name": "New User", "gender": "male", "birthdate": "1995-04-23"},
 			expectedStatusCode: http.StatusCreated,
 		},
+		{
+			name:               "SignUp - Birthdate Missing",
+			method:             "POST",
+			url:                "/signup",
+			body:               map[string]string{"email": "newuser@example.com", "password": "newpassword", "phone": "1112223333", "name": "New User", "gender": "male"},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "birthdate is required",
+		},
+		{
+			name:               "SignUp - Under 18",
+			method:             "POST",
+			url:                "/signup",
+			body:               map[string]string{"email": "newuser@example.com", "password": "newpassword", "phone": "1112223333", "name": "New User", "gender": "male", "birthdate": time.Now().AddDate(-17, 0, 0).Format("2006-01-02")},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "you must be at least 18 years old",
+		},
+		{
+			name:               "SignUp - Email Already Exists",
+			method:             "POST",
+			url:                "/signup",
+			body:               map[string]string{"email": "test1@example.com", "password": "newpassword", "phone": "1112223333", "name": "New User", "gender": "male", "birthdate": "1995-04-23"},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "SignUp - Phone Already Exists",
+			method:             "POST",
+			url:                "/signup",
+			body:               map[string]string{"email": "newuser@example.com", "password": "newpassword", "phone": "1234567890", "name": "New User", "gender": "male", "birthdate": "1995-04-23"},
+			expectedStatusCode: http.StatusConflict,
+		},
 		{
 			name:               "Login - Success",
 			method:             "POST",
@@ -0,0 +1,72 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doRequest sends a JSON request through the router and decodes the "data" envelope of the response
+func doRequest(t *testing.T, router *mux.Router, method, url, token string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, url, bytes.NewReader(payload))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	return rr, response.Data
+}
+
+func TestRefreshAndLogoutFlow(t *testing.T) {
+	testRepo := NewResettableTestRepository(repositories.NewUserRepository())
+	testRepo.SeedTestData()
+	router := routes.SetupRouterWithRepo(testRepo.GetRepository())
+
+	rr, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "test1@example.com", "password": "password1"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	accessToken := login["token"].(string)
+	refreshToken := login["refresh_token"].(string)
+	assert.NotEmpty(t, refreshToken)
+
+	// Refreshing rotates the refresh token, the old one can't be used twice
+	rr, refreshed := doRequest(t, router, "POST", "/token/refresh", "", map[string]string{"refresh_token": refreshToken})
+	require.Equal(t, http.StatusOK, rr.Code)
+	newAccessToken := refreshed["token"].(string)
+	newRefreshToken := refreshed["refresh_token"].(string)
+	assert.NotEqual(t, refreshToken, newRefreshToken)
+
+	rr, _ = doRequest(t, router, "POST", "/token/refresh", "", map[string]string{"refresh_token": refreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Logging out kills the access token it was called with and the refresh token
+	rr, _ = doRequest(t, router, "POST", "/logout", newAccessToken, map[string]string{"refresh_token": newRefreshToken})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr, _ = doRequest(t, router, "GET", "/candidates", newAccessToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr, _ = doRequest(t, router, "POST", "/token/refresh", "", map[string]string{"refresh_token": newRefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Tokens issued earlier are unaffected by the logout
+	rr, _ = doRequest(t, router, "GET", "/candidates", accessToken, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
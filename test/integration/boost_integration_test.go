@@ -0,0 +1,83 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBoostedProfileLeadsTheDeck(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+
+	saveUser := func(id int, gender string, features models.PremiumFeatures) {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("password%d", id)), bcrypt.MinCost)
+		require.NoError(t, repo.SaveUser(&models.User{
+			ID:              id,
+			Email:           fmt.Sprintf("test%d@example.com", id),
+			Password:        string(hashedPassword),
+			Phone:           fmt.Sprint(id),
+			Name:            fmt.Sprintf("User %d", id),
+			Gender:          gender,
+			Birthdate:       models.NewDate(1996, time.March, 1),
+			PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+			PremiumFeatures: features,
+		}))
+	}
+	saveUser(1, "male", models.PremiumFeatures{})
+	saveUser(2, "female", models.PremiumFeatures{UnlimitedSwipes: true})
+	saveUser(3, "female", models.PremiumFeatures{ProfileBoost: true})
+
+	login := func(identifier, password string) string {
+		_, data := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": identifier, "password": password})
+		return data["token"].(string)
+	}
+
+	// Without a plan that includes it there is nothing to boost
+	rr, _ := doRequest(t, router, "POST", "/boost", login("test2@example.com", "password2"), nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	boosterToken := login("test3@example.com", "password3")
+	rr, data := doRequest(t, router, "POST", "/boost", boosterToken, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, data["boosted_until"])
+
+	rr, _ = doRequest(t, router, "POST", "/boost", boosterToken, nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// The boosted profile comes before the one with the lower ID
+	rr, _ = doRequest(t, router, "GET", "/candidates", login("test1@example.com", "password1"), nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var candidates struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &candidates))
+	require.Len(t, candidates.Data, 2)
+	assert.Equal(t, float64(3), candidates.Data[0]["id"])
+	assert.Equal(t, float64(2), candidates.Data[1]["id"])
+	assert.Equal(t, false, candidates.Data[0]["is_verified"])
+}
+
+func TestSignUpIgnoresBoost(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+
+	boostedUntil := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	rr, _ := doRequest(t, router, "POST", "/signup", "", map[string]interface{}{"email": "him@example.com", "password": "password1", "phone": "1110001111", "name": "Him", "gender": "male", "birthdate": "1995-04-23", "boosted_until": boostedUntil})
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	user, err := repo.GetUserByEmail("him@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, user.BoostedUntil)
+	assert.False(t, user.IsBoosted(time.Now()))
+}
@@ -0,0 +1,59 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidatesPagination(t *testing.T) {
+	router := routes.SetupRouterWithRepo(repositories.NewUserRepository())
+
+	signUp := func(email, phone, gender, bio, occupation string) {
+		rr, _ := doRequest(t, router, "POST", "/signup", "", map[string]string{"email": email, "password": "password1", "phone": phone, "name": email, "gender": gender, "birthdate": "1995-04-23", "bio": bio, "occupation": occupation})
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}
+	signUp("him@example.com", "1110001111", "male", "", "")
+	// The later profiles are more complete, the ranking has to serve them first
+	signUp("her1@example.com", "2220002221", "female", "", "")
+	signUp("her2@example.com", "2220002222", "female", "Hiking", "")
+	signUp("her3@example.com", "2220002223", "female", "Hiking", "Nurse")
+
+	rr, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "him@example.com", "password": "password1"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	token := login["token"].(string)
+
+	getPage := func(url string) (int, []string, *string) {
+		rr, _ := doRequest(t, router, "GET", url, token, nil)
+		var response struct {
+			Data       []map[string]interface{} `json:"data"`
+			NextCursor *string                  `json:"next_cursor"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		var names []string
+		for _, profile := range response.Data {
+			names = append(names, profile["name"].(string))
+		}
+		return rr.Code, names, response.NextCursor
+	}
+
+	code, names, cursor := getPage("/candidates?limit=2")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"her3@example.com", "her2@example.com"}, names)
+	require.NotNil(t, cursor)
+
+	code, names, next := getPage("/candidates?limit=2&cursor=" + *cursor)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"her1@example.com"}, names)
+	assert.Nil(t, next)
+
+	code, _, _ = getPage("/candidates?limit=0")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = getPage("/candidates?cursor=garbage!")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestChatBetweenMatchedUsers(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+
+	saveUser := func(id int, gender string) string {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("password%d", id)), bcrypt.MinCost)
+		require.NoError(t, repo.SaveUser(&models.User{
+			ID:        id,
+			Email:     fmt.Sprintf("test%d@example.com", id),
+			Password:  string(hashedPassword),
+			Phone:     fmt.Sprint(id),
+			Gender:    gender,
+			Birthdate: models.NewDate(1996, time.March, 1),
+		}))
+		_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": fmt.Sprintf("test%d@example.com", id), "password": fmt.Sprintf("password%d", id)})
+		return login["token"].(string)
+	}
+	her := saveUser(1, "female")
+	him := saveUser(2, "male")
+	stranger := saveUser(3, "male")
+
+	rr, _ := doRequest(t, router, "POST", "/swipe", her, map[string]interface{}{"target_user_id": 2, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr, data := doRequest(t, router, "POST", "/swipe", him, map[string]interface{}{"target_user_id": 1, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	matchID := int(data["match"].(map[string]interface{})["id"].(float64))
+	messagesURL := fmt.Sprintf("/conversations/%d/messages", matchID)
+
+	for i, token := range []string{her, him, her} {
+		rr, data = doRequest(t, router, "POST", messagesURL, token, map[string]string{"body": fmt.Sprintf("message %d", i+1)})
+		require.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, fmt.Sprintf("message %d", i+1), data["body"])
+	}
+	rr, _ = doRequest(t, router, "POST", messagesURL, her, map[string]string{"body": " "})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Messages come newest first, a page at a time
+	readPage := func(token, url string) ([]string, interface{}) {
+		rr, _ := doRequest(t, router, "GET", url, token, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var page struct {
+			Data       []models.Message `json:"data"`
+			NextCursor interface{}      `json:"next_cursor"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+		var bodies []string
+		for _, message := range page.Data {
+			bodies = append(bodies, message.Body)
+		}
+		return bodies, page.NextCursor
+	}
+	bodies, next := readPage(him, messagesURL+"?limit=2")
+	assert.Equal(t, []string{"message 3", "message 2"}, bodies)
+	require.NotNil(t, next)
+	bodies, next = readPage(him, messagesURL+"?limit=2&cursor="+next.(string))
+	assert.Equal(t, []string{"message 1"}, bodies)
+	assert.Nil(t, next)
+
+	// Nobody else can read or write, and unmatching ends the conversation
+	rr, _ = doRequest(t, router, "GET", messagesURL, stranger, nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr, _ = doRequest(t, router, "POST", messagesURL, stranger, map[string]string{"body": "hey"})
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr, _ = doRequest(t, router, "DELETE", fmt.Sprintf("/matches/%d", matchID), her, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr, _ = doRequest(t, router, "POST", messagesURL, him, map[string]string{"body": "still there?"})
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
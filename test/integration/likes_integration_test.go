@@ -0,0 +1,87 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestReceivedLikesInbox(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+
+	saveUser := func(id int, gender string, premiumExpiry *time.Time) string {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("password%d", id)), bcrypt.MinCost)
+		require.NoError(t, repo.SaveUser(&models.User{
+			ID:            id,
+			Email:         fmt.Sprintf("test%d@example.com", id),
+			Password:      string(hashedPassword),
+			Phone:         fmt.Sprint(id),
+			Name:          fmt.Sprintf("User %d", id),
+			Gender:        gender,
+			Birthdate:     models.NewDate(1996, time.March, 1),
+			PremiumExpiry: premiumExpiry,
+		}))
+		_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": fmt.Sprintf("test%d@example.com", id), "password": fmt.Sprintf("password%d", id)})
+		return login["token"].(string)
+	}
+	freeToken := saveUser(1, "female", nil)
+	premiumToken := saveUser(2, "female", utils.TimePtr(time.Now().Add(24*time.Hour)))
+	firstLiker := saveUser(3, "male", nil)
+	secondLiker := saveUser(4, "male", nil)
+
+	for _, swipe := range []struct {
+		token  string
+		target int
+		action string
+	}{
+		{firstLiker, 1, "like"},
+		{firstLiker, 2, "like"},
+		{secondLiker, 1, "like"},
+		{secondLiker, 2, "super_like"},
+	} {
+		rr, _ := doRequest(t, router, "POST", "/swipe", swipe.token, map[string]interface{}{"target_user_id": swipe.target, "action": swipe.action})
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	// Free users only learn how many likes are waiting
+	rr, data := doRequest(t, router, "GET", "/likes/received", freeToken, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(2), data["count"])
+	assert.Equal(t, true, data["blurred"])
+	for _, like := range data["likes"].([]interface{}) {
+		assert.NotContains(t, like, "profile")
+		assert.NotContains(t, like, "liked_at")
+	}
+
+	// Premium users see who liked them, newest first
+	rr, data = doRequest(t, router, "GET", "/likes/received", premiumToken, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(2), data["count"])
+	assert.Equal(t, false, data["blurred"])
+	likes := data["likes"].([]interface{})
+	require.Len(t, likes, 2)
+	newest := likes[0].(map[string]interface{})
+	assert.Equal(t, float64(4), newest["profile"].(map[string]interface{})["id"])
+	assert.Equal(t, true, newest["super_like"])
+	assert.NotContains(t, newest["profile"], "email")
+
+	// Liking back is a regular swipe and takes the like out of the inbox
+	rr, data = doRequest(t, router, "POST", "/swipe", premiumToken, map[string]interface{}{"target_user_id": 4, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, true, data["is_match"])
+
+	rr, data = doRequest(t, router, "GET", "/likes/received", premiumToken, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(1), data["count"])
+	assert.Equal(t, float64(3), data["likes"].([]interface{})[0].(map[string]interface{})["profile"].(map[string]interface{})["id"])
+}
@@ -0,0 +1,109 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNotificationCenter(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+
+	saveUser := func(id int, gender string) string {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("password%d", id)), bcrypt.MinCost)
+		require.NoError(t, repo.SaveUser(&models.User{
+			ID:        id,
+			Email:     fmt.Sprintf("test%d@example.com", id),
+			Password:  string(hashedPassword),
+			Phone:     fmt.Sprint(id),
+			Gender:    gender,
+			Birthdate: models.NewDate(1996, time.March, 1),
+		}))
+		_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": fmt.Sprintf("test%d@example.com", id), "password": fmt.Sprintf("password%d", id)})
+		return login["token"].(string)
+	}
+	her := saveUser(1, "female")
+	likers := []string{saveUser(2, "male"), saveUser(3, "male"), saveUser(4, "male")}
+
+	for _, liker := range likers {
+		rr, _ := doRequest(t, router, "POST", "/swipe", liker, map[string]interface{}{"target_user_id": 1, "action": "like"})
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+	// Liking back notifies her of the match as well
+	rr, _ := doRequest(t, router, "POST", "/swipe", her, map[string]interface{}{"target_user_id": 2, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	type notificationPage struct {
+		Data struct {
+			Notifications []models.Notification `json:"notifications"`
+			UnreadCount   int                   `json:"unread_count"`
+		} `json:"data"`
+		NextCursor *string `json:"next_cursor"`
+	}
+	readPage := func(url string) notificationPage {
+		rr, _ := doRequest(t, router, "GET", url, her, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var page notificationPage
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+		return page
+	}
+
+	page := readPage("/notifications?limit=3")
+	assert.Equal(t, 4, page.Data.UnreadCount)
+	require.Len(t, page.Data.Notifications, 3)
+	assert.Equal(t, models.EventMutualLike, page.Data.Notifications[0].Type)
+	require.NotNil(t, page.NextCursor)
+	next := readPage("/notifications?limit=3&cursor=" + *page.NextCursor)
+	require.Len(t, next.Data.Notifications, 1)
+	assert.Equal(t, models.EventLikeReceived, next.Data.Notifications[0].Type)
+	assert.Nil(t, next.NextCursor)
+
+	rr, data := doRequest(t, router, "POST", "/notifications/read", her, map[string]interface{}{"ids": []int{page.Data.Notifications[0].ID}})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(3), data["unread_count"])
+	page = readPage("/notifications")
+	assert.True(t, page.Data.Notifications[0].Read)
+	assert.False(t, page.Data.Notifications[1].Read)
+
+	// Marking everything has to be asked for, an empty list is a mistake
+	rr, _ = doRequest(t, router, "POST", "/notifications/read", her, map[string]interface{}{"ids": []int{}})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr, _ = doRequest(t, router, "POST", "/notifications/read", her, map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 3, readPage("/notifications").Data.UnreadCount)
+	rr, data = doRequest(t, router, "POST", "/notifications/read", her, map[string]interface{}{"all": true})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(0), data["unread_count"])
+
+	// Other users' notifications can't be marked
+	rr, data = doRequest(t, router, "POST", "/notifications/read", likers[0], map[string]interface{}{"ids": []int{page.Data.Notifications[1].ID}})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(1), data["unread_count"], "only the match notification of the liker is unread")
+	rr, _ = doRequest(t, router, "POST", "/notifications/read", likers[0], nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// A chunked request has no content length, its empty body is rejected all the same
+	req := httptest.NewRequest("POST", "/notifications/read", io.NopCloser(strings.NewReader("")))
+	req.Header.Set("Authorization", "Bearer "+likers[0])
+	chunked := httptest.NewRecorder()
+	router.ServeHTTP(chunked, req)
+	assert.Equal(t, int64(-1), req.ContentLength)
+	assert.Equal(t, http.StatusBadRequest, chunked.Code)
+
+	rr, data = doRequest(t, router, "POST", "/notifications/read", likers[0], map[string]interface{}{"all": true})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, float64(0), data["unread_count"])
+}
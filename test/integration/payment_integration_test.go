@@ -0,0 +1,55 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchaseActivatedByWebhook(t *testing.T) {
+	testRepo := NewResettableTestRepository(repositories.NewUserRepository())
+	testRepo.SeedTestData()
+	router := routes.SetupRouterWithRepo(testRepo.GetRepository())
+
+	_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "test1@example.com", "password": "password1"})
+	token := login["token"].(string)
+
+	rr, data := doRequest(t, router, "POST", "/purchase-premium", token, map[string]string{"plan_id": "monthly"})
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	purchase := data["purchase"].(map[string]interface{})
+	assert.Equal(t, "pending", purchase["status"])
+	assert.Equal(t, "monthly", purchase["plan_id"])
+	assert.NotContains(t, purchase, "user_id")
+
+	user, _ := testRepo.GetRepository().GetUserByEmail("test1@example.com")
+	assert.False(t, user.PremiumFeatures.UnlimitedSwipes, "premium must wait for the payment")
+
+	sendWebhook := func(payload []byte, signature string) int {
+		req := httptest.NewRequest("POST", "/webhooks/payments", bytes.NewReader(payload))
+		req.Header.Set(payments.SignatureHeader, signature)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	payload, _ := json.Marshal(payments.WebhookEvent{Type: payments.WebhookChargeSucceeded, ChargeID: purchase["charge_id"].(string)})
+	assert.Equal(t, http.StatusUnauthorized, sendWebhook(payload, "forged"))
+
+	signature := payments.NewFakeProvider(os.Getenv("PAYMENT_WEBHOOK_SECRET")).SignPayload(payload)
+	assert.Equal(t, http.StatusOK, sendWebhook(payload, signature))
+	// Providers retry deliveries, a replay must not fail or grant anything twice
+	assert.Equal(t, http.StatusOK, sendWebhook(payload, signature))
+
+	user, _ = testRepo.GetRepository().GetUserByEmail("test1@example.com")
+	assert.True(t, user.PremiumFeatures.UnlimitedSwipes)
+	assert.NotNil(t, user.PremiumExpiry)
+}
@@ -0,0 +1,53 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsesDoNotLeakSensitiveFields(t *testing.T) {
+	router := routes.SetupRouterWithRepo(repositories.NewUserRepository())
+
+	rr, _ := doRequest(t, router, "POST", "/signup", "", map[string]string{"email": "him@example.com", "password": "password1", "phone": "1110001111", "name": "Him", "gender": "male", "birthdate": "1995-04-23"})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	rr, _ = doRequest(t, router, "POST", "/signup", "", map[string]string{"email": "her@example.com", "password": "password2", "phone": "2220002222", "name": "Her", "gender": "female", "birthdate": "1997-08-02"})
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	rr, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "him@example.com", "password": "password1"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	token := login["token"].(string)
+
+	// Other users only ever show up as public profiles
+	rr, _ = doRequest(t, router, "GET", "/candidates", token, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var candidates struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &candidates))
+	require.Len(t, candidates.Data, 1)
+	assert.Equal(t, "Her", candidates.Data[0]["name"])
+	assert.NotZero(t, candidates.Data[0]["age"])
+	for _, field := range []string{"password", "email", "phone", "premium_expiry", "premium_features"} {
+		assert.NotContains(t, candidates.Data[0], field)
+	}
+	assert.NotContains(t, rr.Body.String(), "$2a$")
+
+	// The own profile has contact details but still no password hash
+	rr, me := doRequest(t, router, "GET", "/me", token, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "him@example.com", me["email"])
+	assert.Equal(t, "1995-04-23", me["birthdate"])
+	assert.NotContains(t, me, "password")
+	assert.NotContains(t, rr.Body.String(), "$2a$")
+
+	rr, me = doRequest(t, router, "PATCH", "/me", token, map[string]string{"bio": "Hi there"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Hi there", me["bio"])
+	assert.NotContains(t, me, "password")
+}
@@ -0,0 +1,73 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRewindRestoresTheProfileAndQuota(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password1"), bcrypt.MinCost)
+	require.NoError(t, repo.SaveUser(&models.User{
+		ID:              1,
+		Email:           "him@example.com",
+		Password:        string(hashedPassword),
+		Phone:           "1",
+		Gender:          "male",
+		Birthdate:       models.NewDate(1995, time.April, 23),
+		PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+		PremiumFeatures: models.PremiumFeatures{Rewind: true},
+	}))
+	for id := 2; id <= 12; id++ {
+		require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id), Gender: "female", Birthdate: models.NewDate(1996, time.May, 1)}))
+	}
+	_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "him@example.com", "password": "password1"})
+	token := login["token"].(string)
+
+	rr, _ := doRequest(t, router, "POST", "/swipe/rewind", token, nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Use up the daily quota, the last pass was a mistake
+	for id := 2; id <= 11; id++ {
+		rr, _ = doRequest(t, router, "POST", "/swipe", token, map[string]interface{}{"target_user_id": id, "action": "pass"})
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+	rr, _ = doRequest(t, router, "POST", "/swipe", token, map[string]interface{}{"target_user_id": 12, "action": "pass"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr, data := doRequest(t, router, "POST", "/swipe/rewind", token, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rewound := data["swipe"].(map[string]interface{})
+	assert.Equal(t, float64(11), rewound["target_user_id"])
+	assert.Equal(t, "pass", rewound["action"])
+	assert.NotContains(t, rewound, "user_id")
+
+	rr, _ = doRequest(t, router, "GET", "/candidates", token, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var candidates struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &candidates))
+	var ids []float64
+	for _, candidate := range candidates.Data {
+		ids = append(ids, candidate["id"].(float64))
+	}
+	assert.ElementsMatch(t, []float64{11, 12}, ids)
+
+	// The refunded swipe can be used again
+	rr, _ = doRequest(t, router, "POST", "/swipe", token, map[string]interface{}{"target_user_id": 11, "action": "like"})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -25,6 +25,11 @@ func TestMain(m *testing.M) {
 
 	log.Println("JWT_SECRET_KEY:", os.Getenv("JWT_SECRET_KEY"))
 
+	// Set fallback PAYMENT_WEBHOOK_SECRET if not set
+	if os.Getenv("PAYMENT_WEBHOOK_SECRET") == "" {
+		os.Setenv("PAYMENT_WEBHOOK_SECRET", "fallback-webhook-secret")
+	}
+
 	// Run tests
 	os.Exit(m.Run())
 }
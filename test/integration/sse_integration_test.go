@@ -0,0 +1,115 @@
+package integration_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type serverSentEvent struct {
+	ID    string
+	Event string
+	Data  map[string]interface{}
+}
+
+// readServerSentEvent reads the next event of the stream, skipping comments
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) serverSentEvent {
+	var event serverSentEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && event.Event != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.Data))
+		}
+	}
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	saveUser := func(id int, gender string) string {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("password%d", id)), bcrypt.MinCost)
+		require.NoError(t, repo.SaveUser(&models.User{
+			ID:        id,
+			Email:     fmt.Sprintf("test%d@example.com", id),
+			Password:  string(hashedPassword),
+			Phone:     fmt.Sprint(id),
+			Gender:    gender,
+			Birthdate: models.NewDate(1996, time.March, 1),
+		}))
+		_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": fmt.Sprintf("test%d@example.com", id), "password": fmt.Sprintf("password%d", id)})
+		return login["token"].(string)
+	}
+	her := saveUser(1, "female")
+	firstLiker := saveUser(2, "male")
+	secondLiker := saveUser(3, "male")
+
+	openStream := func(url string, header http.Header) *http.Response {
+		req, err := http.NewRequest("GET", server.URL+url, nil)
+		require.NoError(t, err)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := openStream("/events", nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp = openStream("/events", http.Header{"Authorization": {"Bearer " + her}, "Last-Event-Id": {"abc"}})
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	stream := openStream("/events?access_token="+her, nil)
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	rr, _ := doRequest(t, router, "POST", "/swipe", firstLiker, map[string]interface{}{"target_user_id": 1, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	event := readServerSentEvent(t, bufio.NewReader(stream.Body))
+	assert.Equal(t, models.EventLikeReceived, event.Event)
+	assert.Equal(t, event.ID, fmt.Sprint(event.Data["id"]))
+	stream.Body.Close()
+
+	// Events published while disconnected are replayed after the last one received
+	rr, _ = doRequest(t, router, "POST", "/swipe", secondLiker, map[string]interface{}{"target_user_id": 1, "action": "super_like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr, _ = doRequest(t, router, "POST", "/swipe", her, map[string]interface{}{"target_user_id": 2, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	resumed := openStream("/events", http.Header{"Authorization": {"Bearer " + her}, "Last-Event-Id": {event.ID}})
+	defer resumed.Body.Close()
+	require.Equal(t, http.StatusOK, resumed.StatusCode)
+	reader := bufio.NewReader(resumed.Body)
+	missed := readServerSentEvent(t, reader)
+	assert.Equal(t, models.EventLikeReceived, missed.Event)
+	assert.Equal(t, true, missed.Data["data"].(map[string]interface{})["super_like"])
+	missed = readServerSentEvent(t, reader)
+	assert.Equal(t, models.EventMutualLike, missed.Event)
+	assert.Equal(t, float64(2), missed.Data["data"].(map[string]interface{})["user_id"])
+}
@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationFlow(t *testing.T) {
+	os.Setenv("ADMIN_API_KEY", "test-admin-key")
+	defer os.Unsetenv("ADMIN_API_KEY")
+	router := routes.SetupRouterWithRepo(repositories.NewUserRepository())
+
+	rr, _ := doRequest(t, router, "POST", "/signup", "", map[string]string{"email": "him@example.com", "password": "password1", "phone": "1110001111", "name": "Him", "gender": "male", "birthdate": "1995-04-23"})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "him@example.com", "password": "password1"})
+	token := login["token"].(string)
+
+	upload := func(documentType string, document []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		form.WriteField("document_type", documentType)
+		file, _ := form.CreateFormFile("document", "selfie.png")
+		file.Write(document)
+		form.Close()
+
+		req := httptest.NewRequest("POST", "/me/verification", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	admin := func(method, url string, body interface{}, key string) (*httptest.ResponseRecorder, []byte) {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, url, bytes.NewReader(payload))
+		req.Header.Set(middlewares.AdminKeyHeader, key)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr, rr.Body.Bytes()
+	}
+
+	document := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	assert.Equal(t, http.StatusBadRequest, upload("selfie", []byte("not an image")).Code)
+	require.Equal(t, http.StatusAccepted, upload("selfie", document).Code)
+	assert.Equal(t, http.StatusConflict, upload("selfie", document).Code)
+
+	rr, status := doRequest(t, router, "GET", "/me/verification", token, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "pending", status["status"])
+	assert.NotContains(t, status, "reviewed_by")
+	assert.NotContains(t, status, "user_id")
+
+	// The queue is only visible to administrators
+	rr, _ = admin("GET", "/admin/verifications", nil, "wrong-key")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	rr, body := admin("GET", "/admin/verifications", nil, "test-admin-key")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var queue struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &queue))
+	require.Len(t, queue.Data, 1)
+	assert.NotContains(t, queue.Data[0], "document")
+	require.Contains(t, queue.Data[0], "user")
+	user := queue.Data[0]["user"].(map[string]interface{})
+	assert.NotEmpty(t, user["email"])
+	assert.NotContains(t, user, "password")
+
+	rr, body = admin("GET", "/admin/verifications/1/document", nil, "test-admin-key")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, document, body)
+
+	_, me := doRequest(t, router, "GET", "/me", token, nil)
+	assert.Equal(t, false, me["is_verified"])
+
+	rr, _ = admin("POST", "/admin/verifications/1/review", map[string]string{"decision": "approve"}, "test-admin-key")
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr, _ = admin("POST", "/admin/verifications/1/review", map[string]string{"decision": "reject", "reason": "late"}, "test-admin-key")
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	_, me = doRequest(t, router, "GET", "/me", token, nil)
+	assert.Equal(t, true, me["is_verified"])
+	assert.Equal(t, http.StatusConflict, upload("selfie", document).Code)
+}
+
+func TestSignUpIgnoresVerification(t *testing.T) {
+	router := routes.SetupRouterWithRepo(repositories.NewUserRepository())
+
+	rr, _ := doRequest(t, router, "POST", "/signup", "", map[string]interface{}{"email": "him@example.com", "password": "password1", "phone": "1110001111", "name": "Him", "gender": "male", "birthdate": "1995-04-23", "is_verified": true})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": "him@example.com", "password": "password1"})
+
+	_, me := doRequest(t, router, "GET", "/me", login["token"].(string), nil)
+	assert.Equal(t, false, me["is_verified"])
+}
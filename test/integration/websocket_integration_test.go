@@ -0,0 +1,90 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/routes"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestWebSocketPushesSwipeEvents(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	router := routes.SetupRouterWithRepo(repo)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	saveUser := func(id int, gender string) string {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("password%d", id)), bcrypt.MinCost)
+		require.NoError(t, repo.SaveUser(&models.User{
+			ID:        id,
+			Email:     fmt.Sprintf("test%d@example.com", id),
+			Password:  string(hashedPassword),
+			Phone:     fmt.Sprint(id),
+			Gender:    gender,
+			Birthdate: models.NewDate(1996, time.March, 1),
+		}))
+		_, login := doRequest(t, router, "POST", "/login", "", map[string]string{"identifier": fmt.Sprintf("test%d@example.com", id), "password": fmt.Sprintf("password%d", id)})
+		return login["token"].(string)
+	}
+	her := saveUser(1, "female")
+	him := saveUser(2, "male")
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_, resp, err = websocket.DefaultDialer.Dial(wsURL+"?access_token=invalid", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// The same user may be connected from several devices, with a header or the query parameter
+	phone, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": {"Bearer " + her}})
+	require.NoError(t, err)
+	defer phone.Close()
+	browser, _, err := websocket.DefaultDialer.Dial(wsURL+"?access_token="+her, nil)
+	require.NoError(t, err)
+	defer browser.Close()
+	hisConnection, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": {"Bearer " + him}})
+	require.NoError(t, err)
+	defer hisConnection.Close()
+
+	readEvent := func(conn *websocket.Conn) map[string]interface{} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		var event map[string]interface{}
+		require.NoError(t, conn.ReadJSON(&event))
+		return event
+	}
+
+	rr, _ := doRequest(t, router, "POST", "/swipe", him, map[string]interface{}{"target_user_id": 1, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	for _, conn := range []*websocket.Conn{phone, browser} {
+		event := readEvent(conn)
+		assert.Equal(t, models.EventLikeReceived, event["type"])
+		assert.Equal(t, false, event["data"].(map[string]interface{})["super_like"])
+	}
+
+	rr, _ = doRequest(t, router, "POST", "/swipe", her, map[string]interface{}{"target_user_id": 2, "action": "like"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	for conn, otherUser := range map[*websocket.Conn]float64{phone: 2, browser: 2, hisConnection: 1} {
+		event := readEvent(conn)
+		assert.Equal(t, models.EventMutualLike, event["type"])
+		assert.Equal(t, otherUser, event["data"].(map[string]interface{})["user_id"])
+	}
+
+	// Revoked tokens can't open new connections
+	rr, _ = doRequest(t, router, "POST", "/logout", him, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	_, resp, err = websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": {"Bearer " + him}})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,14 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(event models.Event) {
+	m.Called(event)
+}
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMatchRepository struct {
+	mock.Mock
+}
+
+func (m *MockMatchRepository) GenerateMatchID() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *MockMatchRepository) GetMatchByID(matchID int) (*models.Match, error) {
+	args := m.Called(matchID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Match), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMatchRepository) GetMatchBetween(userID, otherUserID int) (*models.Match, error) {
+	args := m.Called(userID, otherUserID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Match), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMatchRepository) GetMatchesForUser(userID int) []models.Match {
+	args := m.Called(userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Match)
+	}
+	return nil
+}
+
+func (m *MockMatchRepository) SaveMatch(match *models.Match) error {
+	args := m.Called(match)
+	return args.Error(0)
+}
+
+func (m *MockMatchRepository) DeleteMatch(matchID int, unmatchedAt time.Time) error {
+	args := m.Called(matchID, unmatchedAt)
+	return args.Error(0)
+}
+
+func (m *MockMatchRepository) GetUnmatchesForUser(userID int) map[int]time.Time {
+	args := m.Called(userID)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[int]time.Time)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMessageRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageRepository) GetConversationByID(conversationID int) (*models.Conversation, error) {
+	args := m.Called(conversationID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Conversation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageRepository) SaveConversation(conversation *models.Conversation) error {
+	args := m.Called(conversation)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) GenerateMessageID() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *MockMessageRepository) GetMessagesBefore(conversationID int, beforeID int, limit int) []models.Message {
+	args := m.Called(conversationID, beforeID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Message)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) SaveMessage(message *models.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
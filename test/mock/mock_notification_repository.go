@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) GetNotificationsBefore(userID int, beforeID int, limit int) []models.Notification {
+	args := m.Called(userID, beforeID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Notification)
+	}
+	return nil
+}
+
+func (m *MockNotificationRepository) CountUnreadNotifications(userID int) int {
+	args := m.Called(userID)
+	return args.Int(0)
+}
+
+func (m *MockNotificationRepository) SaveNotification(notification *models.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkNotificationsRead(userID int, notificationIDs []int) int {
+	args := m.Called(userID, notificationIDs)
+	return args.Int(0)
+}
+
+func (m *MockNotificationRepository) MarkAllNotificationsRead(userID int) int {
+	args := m.Called(userID)
+	return args.Int(0)
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentProvider struct {
+	mock.Mock
+}
+
+func (m *MockPaymentProvider) CreateCharge(amount int64, currency string, reference string) (*payments.Charge, error) {
+	args := m.Called(amount, currency, reference)
+	if args.Get(0) != nil {
+		return args.Get(0).(*payments.Charge), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentProvider) CaptureCharge(chargeID string) (*payments.Charge, error) {
+	args := m.Called(chargeID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*payments.Charge), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentProvider) RefundCharge(chargeID string) (*payments.Charge, error) {
+	args := m.Called(chargeID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*payments.Charge), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentProvider) VerifyWebhookSignature(payload []byte, signature string) error {
+	args := m.Called(payload, signature)
+	return args.Error(0)
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlanRepository) GetAllPlans() []models.Plan {
+	args := m.Called()
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Plan)
+	}
+	return nil
+}
+
+func (m *MockPlanRepository) GetPlanByID(planID string) (*models.Plan, error) {
+	args := m.Called(planID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Plan), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPremiumEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockPremiumEventRepository) SavePremiumEvent(event *models.PremiumEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockPremiumEventRepository) GetPremiumEventsForUser(userID int) []models.PremiumEvent {
+	args := m.Called(userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.PremiumEvent)
+	}
+	return nil
+}
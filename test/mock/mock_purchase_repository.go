@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPurchaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseRepository) GeneratePurchaseID() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *MockPurchaseRepository) GetPurchaseByID(purchaseID int) (*models.Purchase, error) {
+	args := m.Called(purchaseID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Purchase), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPurchaseRepository) GetPurchaseByChargeID(chargeID string) (*models.Purchase, error) {
+	args := m.Called(chargeID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Purchase), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPurchaseRepository) SavePurchase(purchase *models.Purchase) error {
+	args := m.Called(purchase)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseRepository) UpdatePurchase(purchase *models.Purchase) error {
+	args := m.Called(purchase)
+	return args.Error(0)
+}
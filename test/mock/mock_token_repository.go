@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) SaveRefreshToken(token *models.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.RefreshToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTokenRepository) UpdateRefreshToken(token *models.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeRefreshToken(tokenHash string, now time.Time) (*models.RefreshToken, error) {
+	args := m.Called(tokenHash, now)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.RefreshToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	args := m.Called(jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) IsAccessTokenRevoked(jti string) bool {
+	args := m.Called(jti)
+	return args.Bool(0)
+}
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"time"
+
 	"github.com/GradiyantoS/go-dealls-test-app/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,6 +24,38 @@ func (m *MockUserRepository) GetAllUsers() []*models.User {
 	return nil
 }
 
+func (m *MockUserRepository) GetSwipeActivity(userIDs []int, asOf time.Time) (map[int]models.SwipeActivity, error) {
+	args := m.Called(userIDs, asOf)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[int]models.SwipeActivity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) GetUsersAfter(afterID int, limit int) []*models.User {
+	args := m.Called(afterID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*models.User)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) GetUsersNear(center models.Location, radiusKm float64) []*models.User {
+	args := m.Called(center, radiusKm)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*models.User)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) GetBoostedUsers(now time.Time) []*models.User {
+	args := m.Called(now)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*models.User)
+	}
+	return nil
+}
+
 func (m *MockUserRepository) GetUserByID(userID int) (*models.User, error) {
 	args := m.Called(userID)
 	if args.Get(0) != nil {
@@ -64,7 +98,20 @@ func (m *MockUserRepository) GetSwipesForUser(userID int) []models.Swipe {
 	return nil
 }
 
+func (m *MockUserRepository) GetSwipesReceived(targetUserID int) []models.Swipe {
+	args := m.Called(targetUserID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Swipe)
+	}
+	return nil
+}
+
 func (m *MockUserRepository) SaveSwipe(swipe *models.Swipe) error {
 	args := m.Called(swipe)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) DeleteSwipe(swipeID int) error {
+	args := m.Called(swipeID)
+	return args.Error(0)
+}
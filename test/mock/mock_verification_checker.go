@@ -0,0 +1,20 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/verification"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVerificationChecker struct {
+	mock.Mock
+}
+
+func (m *MockVerificationChecker) Name() string {
+	return "mock"
+}
+
+func (m *MockVerificationChecker) Check(request models.VerificationRequest) (verification.Decision, error) {
+	args := m.Called(request)
+	return args.Get(0).(verification.Decision), args.Error(1)
+}
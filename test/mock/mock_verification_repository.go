@@ -0,0 +1,49 @@
+package mock
+
+import (
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockVerificationRepository) GenerateVerificationID() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *MockVerificationRepository) GetVerificationByID(verificationID int) (*models.VerificationRequest, error) {
+	args := m.Called(verificationID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.VerificationRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockVerificationRepository) GetLatestVerificationForUser(userID int) (*models.VerificationRequest, error) {
+	args := m.Called(userID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.VerificationRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockVerificationRepository) GetPendingVerifications() []models.VerificationRequest {
+	args := m.Called()
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.VerificationRequest)
+	}
+	return nil
+}
+
+func (m *MockVerificationRepository) SaveVerification(request *models.VerificationRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockVerificationRepository) UpdateVerification(request *models.VerificationRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
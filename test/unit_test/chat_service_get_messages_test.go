@@ -0,0 +1,131 @@
+package unit_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMessages(t *testing.T) {
+	mockMatchRepo := new(userMock.MockMatchRepository)
+	mockMessageRepo := new(userMock.MockMessageRepository)
+	service := services.NewChatService(mockMatchRepo, mockMessageRepo)
+
+	match := &models.Match{ID: 5, UserID: 2, MatchedID: 1}
+	messages := func(ids ...int) []models.Message {
+		result := []models.Message{}
+		for _, id := range ids {
+			result = append(result, models.Message{ID: id, ConversationID: 5})
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		userID        int
+		expectedIDs   []int
+		expectedNext  bool
+		expectedError error
+	}{
+		{
+			name: "Success - Last Page",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+				mockMessageRepo.On("GetMessagesBefore", 5, 0, 3).Return(messages(2, 1))
+			},
+			userID:      1,
+			expectedIDs: []int{2, 1},
+		},
+		{
+			name: "Success - More Pages",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+				mockMessageRepo.On("GetMessagesBefore", 5, 0, 3).Return(messages(7, 6, 4))
+			},
+			userID:       2,
+			expectedIDs:  []int{7, 6},
+			expectedNext: true,
+		},
+		{
+			name: "Success - Conversation Not Started",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+				mockMessageRepo.On("GetMessagesBefore", 5, 0, 3).Return(messages())
+			},
+			userID:      1,
+			expectedIDs: []int{},
+		},
+		{
+			name: "Error - Not Part Of The Match",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+			},
+			userID:        3,
+			expectedError: services.ErrConversationNotFound,
+		},
+		{
+			name: "Error - Unmatched",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(nil, errors.New("match not found"))
+			},
+			userID:        1,
+			expectedError: services.ErrConversationNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockMatchRepo.ExpectedCalls = nil
+			mockMessageRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			page, err := service.GetMessages(tc.userID, 5, "", 2)
+
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+				ids := []int{}
+				for _, message := range page.Messages {
+					ids = append(ids, message.ID)
+				}
+				assert.Equal(t, tc.expectedIDs, ids)
+				assert.Equal(t, tc.expectedNext, page.NextCursor != "")
+			} else {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, page)
+			}
+
+			mockMatchRepo.AssertExpectations(t)
+			mockMessageRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Next Cursor Continues Before The Oldest Message", func(t *testing.T) {
+		mockMatchRepo.ExpectedCalls = nil
+		mockMessageRepo.ExpectedCalls = nil
+		mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+		mockMessageRepo.On("GetMessagesBefore", 5, 0, 3).Return(messages(7, 6, 4)).Once()
+		mockMessageRepo.On("GetMessagesBefore", 5, 6, 3).Return(messages(4)).Once()
+
+		page, err := service.GetMessages(1, 5, "", 2)
+		require.NoError(t, err)
+		page, err = service.GetMessages(1, 5, page.NextCursor, 2)
+		require.NoError(t, err)
+		assert.Len(t, page.Messages, 1)
+		assert.Empty(t, page.NextCursor)
+
+		_, err = service.GetMessages(1, 5, "not-a-cursor", 2)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+		// Deck cursors carry segment fields that mean nothing for a conversation
+		_, err = service.GetMessages(1, 5, base64.RawURLEncoding.EncodeToString([]byte(`{"b":true,"id":6}`)), 2)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+
+		mockMessageRepo.AssertExpectations(t)
+	})
+}
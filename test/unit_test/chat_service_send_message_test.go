@@ -0,0 +1,110 @@
+package unit_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendMessage(t *testing.T) {
+	mockMatchRepo := new(userMock.MockMatchRepository)
+	mockMessageRepo := new(userMock.MockMessageRepository)
+	service := services.NewChatService(mockMatchRepo, mockMessageRepo)
+
+	match := &models.Match{ID: 5, UserID: 2, MatchedID: 1}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		userID        int
+		body          string
+		expectedError error
+	}{
+		{
+			name: "Success - First Message Starts The Conversation",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+				mockMessageRepo.On("GetConversationByID", 5).Return(nil, errors.New("conversation not found"))
+				mockMessageRepo.On("SaveConversation", mock.MatchedBy(func(conversation *models.Conversation) bool {
+					return conversation.ID == 5 && conversation.HasUser(1) && conversation.HasUser(2)
+				})).Return(nil)
+				mockMessageRepo.On("GenerateMessageID").Return(9)
+				mockMessageRepo.On("SaveMessage", mock.MatchedBy(func(message *models.Message) bool {
+					return message.ID == 9 && message.ConversationID == 5 && message.SenderID == 1 && message.Body == "hi there"
+				})).Return(nil)
+			},
+			userID: 1,
+			body:   "  hi there ",
+		},
+		{
+			name: "Success - Existing Conversation",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+				mockMessageRepo.On("GetConversationByID", 5).Return(&models.Conversation{ID: 5, UserID: 2, OtherUserID: 1}, nil)
+				mockMessageRepo.On("GenerateMessageID").Return(10)
+				mockMessageRepo.On("SaveMessage", mock.AnythingOfType("*models.Message")).Return(nil)
+			},
+			userID: 2,
+			body:   "hello",
+		},
+		{
+			name: "Error - Not Part Of The Match",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(match, nil)
+			},
+			userID:        3,
+			body:          "hello",
+			expectedError: services.ErrConversationNotFound,
+		},
+		{
+			name: "Error - Unmatched",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 5).Return(nil, errors.New("match not found"))
+			},
+			userID:        1,
+			body:          "hello",
+			expectedError: services.ErrConversationNotFound,
+		},
+		{
+			name:          "Error - Blank Message",
+			setupMocks:    func() {},
+			userID:        1,
+			body:          "   ",
+			expectedError: services.ErrInvalidMessage,
+		},
+		{
+			name:          "Error - Message Too Long",
+			setupMocks:    func() {},
+			userID:        1,
+			body:          strings.Repeat("é", services.MaxMessageLength+1),
+			expectedError: services.ErrInvalidMessage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockMatchRepo.ExpectedCalls = nil
+			mockMessageRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			message, err := service.SendMessage(tc.userID, 5, tc.body)
+
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.userID, message.SenderID)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, message)
+			}
+
+			mockMatchRepo.AssertExpectations(t)
+			mockMessageRepo.AssertExpectations(t)
+		})
+	}
+}
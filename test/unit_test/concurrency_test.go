@@ -0,0 +1,168 @@
+package unit_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests are meant to be run with `go test -race`, they hammer the services
+// backed by the real repositories from many goroutines at once.
+
+func TestConcurrentSignUp(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			service := services.NewUserService(repo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+
+			const workers = 10
+			var wg sync.WaitGroup
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					err := service.SignUp(&models.User{
+						Email:     fmt.Sprintf("user%d@example.com", i),
+						Password:  "password",
+						Phone:     fmt.Sprintf("08%08d", i),
+						Gender:    "male",
+						Birthdate: models.NewDate(1995, time.April, 23),
+					})
+					assert.NoError(t, err)
+				}(i)
+			}
+			wg.Wait()
+
+			users := repo.GetAllUsers()
+			assert.Len(t, users, workers)
+
+			ids := map[int]bool{}
+			for _, user := range users {
+				assert.False(t, ids[user.ID], "duplicate user ID %d", user.ID)
+				ids[user.ID] = true
+			}
+		})
+	}
+}
+
+func TestConcurrentSignUpSameEmail(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			service := services.NewUserService(repo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+
+			var succeeded int32
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					err := service.SignUp(&models.User{
+						Email:     "same@example.com",
+						Password:  "password",
+						Phone:     fmt.Sprintf("08%08d", i),
+						Birthdate: models.NewDate(1995, time.April, 23),
+					})
+					if err == nil {
+						atomic.AddInt32(&succeeded, 1)
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			assert.Equal(t, int32(1), succeeded)
+			assert.Len(t, repo.GetAllUsers(), 1)
+		})
+	}
+}
+
+func TestConcurrentSwipesAndCandidates(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			swipeService := services.NewSwipeService(repo, repositories.NewMatchRepository())
+
+			// Users are saved directly, hashing passwords under the race detector is too slow
+			swiper := &models.User{ID: repo.GenerateUserID(), Email: "swiper@example.com", Phone: "000", Gender: "male"}
+			require.NoError(t, repo.SaveUser(swiper))
+
+			var targets []int
+			for i := 0; i < 30; i++ {
+				target := &models.User{
+					ID:     repo.GenerateUserID(),
+					Email:  fmt.Sprintf("target%d@example.com", i),
+					Phone:  fmt.Sprintf("09%08d", i),
+					Gender: "female",
+				}
+				require.NoError(t, repo.SaveUser(target))
+				targets = append(targets, target.ID)
+			}
+
+			var recorded int32
+			var wg sync.WaitGroup
+			for _, targetID := range targets {
+				// Every target is swiped twice, the duplicate must be rejected
+				for j := 0; j < 2; j++ {
+					wg.Add(1)
+					go func(targetID int) {
+						defer wg.Done()
+						if _, err := swipeService.RecordSwipe(&models.Swipe{UserID: swiper.ID, TargetUserID: targetID, Action: "like"}); err == nil {
+							atomic.AddInt32(&recorded, 1)
+						}
+					}(targetID)
+				}
+
+				wg.Add(1)
+				go func(targetID int) {
+					defer wg.Done()
+					_, err := swipeService.GetSwipeCandidates(swiper.ID, "", 0)
+					assert.NoError(t, err)
+					// Liking back concurrently exercises match creation
+					_, err = swipeService.RecordSwipe(&models.Swipe{UserID: targetID, TargetUserID: swiper.ID, Action: "like"})
+					assert.NoError(t, err)
+				}(targetID)
+			}
+			wg.Wait()
+
+			assert.Equal(t, int32(10), recorded, "daily swipe limit must hold under concurrency")
+			assert.Len(t, repo.GetSwipesForUser(swiper.ID), 10)
+		})
+	}
+}
+
+func TestConcurrentRefreshSameToken(t *testing.T) {
+	originalGenerateJWT := utils.GenerateJWT
+	utils.GenerateJWT = func(userID int) (string, error) {
+		return "mocked-jwt-token", nil
+	}
+	defer func() { utils.GenerateJWT = originalGenerateJWT }()
+
+	service := services.NewTokenService(repositories.NewTokenRepository())
+	tokens, err := service.IssueTokens(1)
+	require.NoError(t, err)
+
+	// A replayed refresh token must only be exchanged once, however close the requests are
+	var succeeded int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.RefreshTokens(tokens.RefreshToken); err == nil {
+				atomic.AddInt32(&succeeded, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), succeeded)
+}
@@ -0,0 +1,68 @@
+package unit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPlans(t *testing.T) {
+	writeCatalogue := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "plans.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Success - Bundled Catalogue", func(t *testing.T) {
+		plans, err := config.LoadPlans("")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, plans)
+	})
+
+	t.Run("Success - Custom Catalogue", func(t *testing.T) {
+		path := writeCatalogue(t, `[{"id":"weekly","name":"Weekly","duration_days":7,"price":15000,"currency":"IDR","features":["UnlimitedSwipes"]}]`)
+		plans, err := config.LoadPlans(path)
+		assert.NoError(t, err)
+		require.Len(t, plans, 1)
+		assert.Equal(t, "weekly", plans[0].ID)
+		assert.Equal(t, 7, plans[0].DurationDays)
+	})
+
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "Error - Duplicate Plan",
+			content:       `[{"id":"a","duration_days":1,"currency":"IDR","features":["IsVerified"]},{"id":"a","duration_days":1,"currency":"IDR","features":["IsVerified"]}]`,
+			expectedError: "invalid plan catalogue: duplicate plan id a",
+		},
+		{
+			name:          "Error - Unknown Feature",
+			content:       `[{"id":"a","duration_days":1,"currency":"IDR","features":["Teleport"]}]`,
+			expectedError: "invalid plan catalogue: plan a has unknown feature Teleport",
+		},
+		{
+			name:          "Error - Missing Duration",
+			content:       `[{"id":"a","currency":"IDR","features":["IsVerified"]}]`,
+			expectedError: "invalid plan catalogue: plan a must have a positive duration",
+		},
+		{
+			name:          "Error - Empty Catalogue",
+			content:       `[]`,
+			expectedError: "invalid plan catalogue: no plans defined",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := config.LoadPlans(writeCatalogue(t, tc.content))
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
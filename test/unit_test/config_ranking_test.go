@@ -0,0 +1,58 @@
+package unit_test
+
+import (
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/config"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRankingWeights(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expected      services.RankingWeights
+		expectedError string
+	}{
+		{
+			name:     "Success - Defaults",
+			value:    "",
+			expected: services.DefaultRankingWeights,
+		},
+		{
+			name:  "Success - Overrides Listed Weights",
+			value: "recency=0.5, interests=0, proximity=1",
+			expected: services.RankingWeights{
+				Recency:         0.5,
+				Completeness:    services.DefaultRankingWeights.Completeness,
+				InterestOverlap: 0,
+				Desirability:    services.DefaultRankingWeights.Desirability,
+				Proximity:       1,
+			},
+		},
+		{
+			name:          "Error - Unknown Scorer",
+			value:         "looks=1",
+			expectedError: "invalid ranking weights: unknown entry looks=1",
+		},
+		{
+			name:          "Error - Negative Weight",
+			value:         "recency=-1",
+			expectedError: "invalid ranking weights: recency must be a non-negative number",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			weights, err := config.LoadRankingWeights(tc.value)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expected, weights)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
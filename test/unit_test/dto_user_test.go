@@ -0,0 +1,132 @@
+package unit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/dto"
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDTOs(t *testing.T) {
+	user := &models.User{
+		ID:              7,
+		Email:           "secret@example.com",
+		Password:        "$2a$10$hashedpassword",
+		Phone:           "5550001111",
+		Name:            "User 7",
+		Gender:          "female",
+		Bio:             "Hello",
+		Location:        &models.Location{Latitude: -6.2, Longitude: 106.8},
+		IsInactive:      true,
+		PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+		PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true},
+	}
+
+	request := &models.VerificationRequest{
+		ID:           1,
+		UserID:       user.ID,
+		DocumentType: models.VerificationDocumentSelfie,
+		ContentType:  "image/png",
+		Document:     []byte("\x89PNG"),
+		Status:       models.VerificationStatusApproved,
+		ReviewedBy:   models.VerificationReviewerAdmin,
+	}
+
+	toJSON := func(value interface{}) map[string]interface{} {
+		raw, err := json.Marshal(value)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), user.Password)
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(raw, &fields))
+		return fields
+	}
+
+	testCases := []struct {
+		name           string
+		value          interface{}
+		expectedFields []string
+		hiddenFields   []string
+	}{
+		{
+			name:           "Public Profile",
+			value:          dto.NewPublicProfile(user),
+			expectedFields: []string{"id", "name", "gender", "bio", "occupation", "interests", "is_verified"},
+			hiddenFields:   []string{"password", "email", "phone", "location", "birthdate", "premium_expiry", "premium_features", "is_inactive"},
+		},
+		{
+			name:           "Public Profiles",
+			value:          dto.NewPublicProfiles([]models.User{*user})[0],
+			expectedFields: []string{"id", "name"},
+			hiddenFields:   []string{"password", "email", "phone", "premium_expiry", "premium_features"},
+		},
+		{
+			name:           "Self Profile",
+			value:          dto.NewSelfProfile(user),
+			expectedFields: []string{"id", "email", "phone", "premium_expiry", "premium_features"},
+			hiddenFields:   []string{"password", "is_inactive"},
+		},
+		{
+			name:           "Admin View",
+			value:          dto.NewAdminUser(user),
+			expectedFields: []string{"id", "email", "phone", "is_inactive"},
+			hiddenFields:   []string{"password"},
+		},
+		{
+			name:           "Model",
+			value:          user,
+			expectedFields: []string{"id", "email"},
+			hiddenFields:   []string{"password"},
+		},
+		{
+			name:           "Verification Request",
+			value:          dto.NewVerificationRequest(request),
+			expectedFields: []string{"id", "document_type", "status", "created_at"},
+			hiddenFields:   []string{"document", "user_id", "content_type", "reviewed_by"},
+		},
+		{
+			name:           "Admin Verification Request",
+			value:          dto.NewAdminVerificationQueue([]models.PendingVerification{{Request: *request, User: user}})[0],
+			expectedFields: []string{"id", "status", "user_id", "content_type", "reviewed_by", "user"},
+			hiddenFields:   []string{"document"},
+		},
+		{
+			name:           "Notification",
+			value:          dto.NewNotifications([]models.Notification{{ID: 3, UserID: 7, Type: models.EventMutualLike}})[0],
+			expectedFields: []string{"id", "type", "read", "created_at"},
+			hiddenFields:   []string{"user_id"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := toJSON(tc.value)
+			for _, field := range tc.expectedFields {
+				assert.Contains(t, fields, field)
+			}
+			for _, field := range tc.hiddenFields {
+				assert.NotContains(t, fields, field)
+			}
+		})
+	}
+}
+
+func TestCandidateProfilesRoundDistance(t *testing.T) {
+	distance := func(km float64) *float64 { return &km }
+
+	profiles := dto.NewCandidateProfiles([]models.Candidate{
+		{User: models.User{ID: 1}, DistanceKm: distance(0.2)},
+		{User: models.User{ID: 2}, DistanceKm: distance(4.01)},
+		{User: models.User{ID: 3}},
+	})
+
+	require.Len(t, profiles, 3)
+	assert.Equal(t, 1, *profiles[0].DistanceKm)
+	assert.Equal(t, 5, *profiles[1].DistanceKm)
+	assert.Nil(t, profiles[2].DistanceKm)
+}
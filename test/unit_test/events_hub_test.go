@@ -0,0 +1,82 @@
+package unit_test
+
+import (
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/events"
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubFansOutToEverySubscriptionOfTheUser(t *testing.T) {
+	hub := events.NewHub()
+	phone := hub.Subscribe(1)
+	laptop := hub.Subscribe(1)
+	other := hub.Subscribe(2)
+
+	hub.Publish(models.Event{Type: models.EventLikeReceived, UserID: 1})
+
+	assert.Equal(t, models.EventLikeReceived, (<-phone.Events()).Type)
+	assert.Equal(t, models.EventLikeReceived, (<-laptop.Events()).Type)
+	assert.Empty(t, other.Events())
+
+	// Closed subscriptions stop receiving, closing twice is fine
+	laptop.Close()
+	laptop.Close()
+	_, open := <-laptop.Events()
+	assert.False(t, open)
+	hub.Publish(models.Event{Type: models.EventQuotaReset, UserID: 1})
+	assert.Equal(t, models.EventQuotaReset, (<-phone.Events()).Type)
+
+	// Nobody listening is fine as well
+	hub.Publish(models.Event{Type: models.EventQuotaReset, UserID: 3})
+}
+
+func TestHubDropsSlowSubscriptions(t *testing.T) {
+	hub := events.NewHub()
+	slow := hub.Subscribe(1)
+
+	received := 0
+	for i := 0; i < 100; i++ {
+		hub.Publish(models.Event{Type: models.EventLikeReceived, UserID: 1})
+	}
+	for range slow.Events() {
+		received++
+	}
+	assert.Less(t, received, 100, "the subscription is closed once its buffer is full")
+
+	// The client reconnects with a new subscription
+	fresh := hub.Subscribe(1)
+	hub.Publish(models.Event{Type: models.EventLikeReceived, UserID: 1})
+	assert.Len(t, fresh.Events(), 1)
+}
+
+func TestHubResumesFromTheLastEventID(t *testing.T) {
+	hub := events.NewHub()
+	for i := 0; i < 3; i++ {
+		hub.Publish(models.Event{Type: models.EventLikeReceived, UserID: 1})
+		hub.Publish(models.Event{Type: models.EventLikeReceived, UserID: 2})
+	}
+
+	// IDs are shared by all users, only the user's own events are replayed
+	subscription, missed := hub.Resume(1, 1)
+	defer subscription.Close()
+	var ids []int
+	for _, event := range missed {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []int{3, 5}, ids)
+
+	// Events published after resuming arrive live
+	hub.Publish(models.Event{Type: models.EventQuotaReset, UserID: 1})
+	assert.Equal(t, 7, (<-subscription.Events()).ID)
+
+	// Only the latest events are kept
+	for i := 0; i < 200; i++ {
+		hub.Publish(models.Event{Type: models.EventLikeReceived, UserID: 2})
+	}
+	resumed, missed := hub.Resume(2, 0)
+	defer resumed.Close()
+	assert.Less(t, len(missed), 200)
+	assert.Equal(t, 207, missed[len(missed)-1].ID)
+}
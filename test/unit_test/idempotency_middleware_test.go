@@ -0,0 +1,98 @@
+package unit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/middlewares"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := middlewares.Idempotency(repositories.NewIdempotencyRepository(), time.Hour)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/slow" {
+				started <- struct{}{}
+				<-release
+			}
+			if r.URL.Path == "/broken" {
+				atomic.AddInt32(&calls, 1)
+				utils.ErrorResponse(w, http.StatusInternalServerError, "boom")
+				return
+			}
+			n := atomic.AddInt32(&calls, 1)
+			utils.DataSuccessResponse(w, http.StatusOK, map[string]int32{"call": n})
+		}))
+
+	send := func(userID int, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), middlewares.UserContextKey, userID))
+		if key != "" {
+			req.Header.Set(middlewares.IdempotencyKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Success - Duplicate Is Replayed", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		first := send(1, "/swipe", "key-1", `{"target_user_id":2}`)
+		second := send(1, "/swipe", "key-1", `{"target_user_id":2}`)
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		assert.Equal(t, first.Code, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(middlewares.IdempotentReplayedHeader))
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	})
+
+	t.Run("Success - Keys Are Scoped Per User", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		send(1, "/swipe", "shared-key", `{}`)
+		send(2, "/swipe", "shared-key", `{}`)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Success - Requests Without Key Always Run", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		send(1, "/swipe", "", `{}`)
+		send(1, "/swipe", "", `{}`)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Error - Key Reused For Different Request", func(t *testing.T) {
+		send(1, "/swipe", "key-2", `{"target_user_id":2}`)
+		rr := send(1, "/swipe", "key-2", `{"target_user_id":3}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Success - Server Errors Are Not Stored", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		send(1, "/broken", "key-3", `{}`)
+		send(1, "/broken", "key-3", `{}`)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Error - Concurrent Duplicate While In Flight", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- send(1, "/slow", "key-4", `{}`) }()
+
+		<-started
+		assert.Equal(t, http.StatusConflict, send(1, "/slow", "key-4", `{}`).Code)
+
+		close(release)
+		assert.Equal(t, http.StatusOK, (<-done).Code)
+		assert.Equal(t, "true", send(1, "/slow", "key-4", `{}`).Header().Get(middlewares.IdempotentReplayedHeader))
+	})
+}
@@ -0,0 +1,89 @@
+package unit_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRepository(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := repositories.NewMatchRepositoryFor(newRepo())
+
+			first := &models.Match{ID: repo.GenerateMatchID(), UserID: 1, MatchedID: 2, CreatedAt: time.Now().UTC()}
+			second := &models.Match{ID: repo.GenerateMatchID(), UserID: 3, MatchedID: 1, CreatedAt: time.Now().UTC()}
+			require.NoError(t, repo.SaveMatch(first))
+			require.NoError(t, repo.SaveMatch(second))
+			assert.Less(t, first.ID, second.ID)
+
+			t.Run("Success - Lookups", func(t *testing.T) {
+				match, err := repo.GetMatchByID(first.ID)
+				require.NoError(t, err)
+				assert.Equal(t, 1, match.UserID)
+				assert.Equal(t, 2, match.MatchedID)
+				assert.WithinDuration(t, first.CreatedAt, match.CreatedAt, time.Second)
+
+				match, err = repo.GetMatchBetween(1, 3)
+				require.NoError(t, err)
+				assert.Equal(t, second.ID, match.ID)
+
+				matches := repo.GetMatchesForUser(1)
+				require.Len(t, matches, 2)
+				assert.Equal(t, []int{first.ID, second.ID}, []int{matches[0].ID, matches[1].ID})
+				assert.Empty(t, repo.GetMatchesForUser(4))
+			})
+
+			t.Run("Error - Duplicate Pair", func(t *testing.T) {
+				err := repo.SaveMatch(&models.Match{ID: repo.GenerateMatchID(), UserID: 2, MatchedID: 1, CreatedAt: time.Now()})
+				assert.EqualError(t, err, "match already exists")
+				err = repo.SaveMatch(&models.Match{ID: first.ID, UserID: 4, MatchedID: 5, CreatedAt: time.Now()})
+				assert.EqualError(t, err, "match ID already exists")
+			})
+
+			t.Run("Success - Delete", func(t *testing.T) {
+				assert.Empty(t, repo.GetUnmatchesForUser(1))
+				unmatchedAt := time.Now().UTC()
+				require.NoError(t, repo.DeleteMatch(first.ID, unmatchedAt))
+				_, err := repo.GetMatchByID(first.ID)
+				assert.EqualError(t, err, "match not found")
+				_, err = repo.GetMatchBetween(2, 1)
+				assert.EqualError(t, err, "match not found")
+				assert.EqualError(t, repo.DeleteMatch(first.ID, unmatchedAt), "match not found")
+
+				// The unmatch is remembered for both sides
+				unmatches := repo.GetUnmatchesForUser(2)
+				require.Len(t, unmatches, 1)
+				assert.WithinDuration(t, unmatchedAt, unmatches[1], time.Millisecond)
+				assert.Contains(t, repo.GetUnmatchesForUser(1), 2)
+				assert.Empty(t, repo.GetUnmatchesForUser(3))
+			})
+
+			t.Run("Success - Unmatching Again Keeps The Latest", func(t *testing.T) {
+				again := &models.Match{ID: repo.GenerateMatchID(), UserID: 2, MatchedID: 1, CreatedAt: time.Now().UTC()}
+				require.NoError(t, repo.SaveMatch(again))
+				unmatchedAt := time.Now().UTC().Add(time.Hour)
+				require.NoError(t, repo.DeleteMatch(again.ID, unmatchedAt))
+				assert.WithinDuration(t, unmatchedAt, repo.GetUnmatchesForUser(1)[2], time.Millisecond)
+			})
+		})
+	}
+}
+
+func TestSQLiteMatchesSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	repo := repositories.NewMatchRepositoryFor(newSQLiteUserRepository(t, path))
+	match := &models.Match{ID: repo.GenerateMatchID(), UserID: 1, MatchedID: 2, CreatedAt: time.Now()}
+	require.NoError(t, repo.SaveMatch(match))
+
+	reopened := repositories.NewMatchRepositoryFor(newSQLiteUserRepository(t, path))
+	_, err := reopened.GetMatchBetween(2, 1)
+	assert.NoError(t, err)
+	// IDs continue after the stored matches
+	assert.Equal(t, match.ID+1, reopened.GenerateMatchID())
+}
@@ -0,0 +1,72 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUnmatch(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockMatchRepo := new(userMock.MockMatchRepository)
+	service := services.NewMatchService(mockRepo, mockMatchRepo)
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		userID        int
+		matchID       int
+		expectedError string
+	}{
+		{
+			name: "Success - Unmatch Own Match",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 1).Return(&models.Match{ID: 1, UserID: 2, MatchedID: 1}, nil)
+				mockMatchRepo.On("DeleteMatch", 1, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			userID:  1,
+			matchID: 1,
+		},
+		{
+			name: "Error - Match Not Found",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 1).Return(nil, errors.New("match not found"))
+			},
+			userID:        1,
+			matchID:       1,
+			expectedError: "match not found",
+		},
+		{
+			name: "Error - Match Of Other Users",
+			setupMocks: func() {
+				mockMatchRepo.On("GetMatchByID", 1).Return(&models.Match{ID: 1, UserID: 2, MatchedID: 3}, nil)
+			},
+			userID:        1,
+			matchID:       1,
+			expectedError: "match not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockMatchRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			err := service.Unmatch(tc.userID, tc.matchID)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockMatchRepo.AssertExpectations(t)
+		})
+	}
+}
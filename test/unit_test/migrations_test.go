@@ -0,0 +1,86 @@
+package unit_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count)
+	require.NoError(t, err)
+	return count == 1
+}
+
+func TestMigrator(t *testing.T) {
+	db := openTestDB(t)
+	migrator := migrations.NewMigrator(db)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	assert.Len(t, statuses, len(migrations.All))
+	for _, status := range statuses {
+		assert.Nil(t, status.AppliedAt)
+	}
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations.All))
+	assert.True(t, tableExists(t, db, "users"))
+	assert.True(t, tableExists(t, db, "swipes"))
+
+	// Running up again is a no-op
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	latest := migrations.All[len(migrations.All)-1]
+	rolledBack, err := migrator.Down()
+	require.NoError(t, err)
+	require.NotNil(t, rolledBack)
+	assert.Equal(t, latest.Version, rolledBack.Version)
+
+	statuses, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+	assert.NotNil(t, statuses[0].AppliedAt)
+
+	// Roll everything back, then down has nothing left to do
+	for range migrations.All[:len(migrations.All)-1] {
+		_, err := migrator.Down()
+		require.NoError(t, err)
+	}
+	assert.False(t, tableExists(t, db, "users"))
+	rolledBack, err = migrator.Down()
+	assert.NoError(t, err)
+	assert.Nil(t, rolledBack)
+}
+
+func TestMigratorOrdersAndRollsBackFailures(t *testing.T) {
+	db := openTestDB(t)
+	migrator := migrations.NewMigratorWith(db, []migrations.Migration{
+		{Version: 2, Name: "broken", Up: `CREATE TABLE b (id INTEGER); INSERT INTO missing VALUES (1);`, Down: `DROP TABLE b;`},
+		{Version: 1, Name: "create_a", Up: `CREATE TABLE a (id INTEGER);`, Down: `DROP TABLE a;`},
+	})
+
+	applied, err := migrator.Up()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "migration broken failed")
+	require.Len(t, applied, 1)
+	assert.Equal(t, "create_a", applied[0].Name)
+	assert.True(t, tableExists(t, db, "a"))
+	assert.False(t, tableExists(t, db, "b"), "failed migration must be rolled back")
+}
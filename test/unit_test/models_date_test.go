@@ -0,0 +1,49 @@
+package unit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateAgeAt(t *testing.T) {
+	birthdate := models.NewDate(2000, time.June, 15)
+
+	testCases := []struct {
+		name        string
+		now         time.Time
+		expectedAge int
+	}{
+		{name: "Day Before Birthday", now: time.Date(2024, time.June, 14, 23, 0, 0, 0, time.UTC), expectedAge: 23},
+		{name: "On Birthday", now: time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC), expectedAge: 24},
+		{name: "Later In The Year", now: time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), expectedAge: 24},
+		{name: "Earlier Month", now: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), expectedAge: 24},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedAge, birthdate.AgeAt(tc.now))
+		})
+	}
+
+	assert.Equal(t, 0, models.Date{}.AgeAt(time.Now()))
+}
+
+func TestDateJSON(t *testing.T) {
+	raw, err := json.Marshal(struct {
+		Birthdate models.Date `json:"birthdate"`
+		Missing   models.Date `json:"missing"`
+	}{Birthdate: models.NewDate(1995, time.April, 23)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"birthdate": "1995-04-23", "missing": null}`, string(raw))
+
+	var decoded models.Date
+	require.NoError(t, json.Unmarshal([]byte(`"1995-04-23"`), &decoded))
+	assert.Equal(t, models.NewDate(1995, time.April, 23), decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`"23/04/1995"`), &decoded))
+}
@@ -0,0 +1,80 @@
+package unit_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepository(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := repositories.NewNotificationRepositoryFor(newRepo())
+
+			var ids []int
+			for i, userID := range []int{1, 2, 1, 1} {
+				notification := &models.Notification{
+					UserID:    userID,
+					Type:      models.EventLikeReceived,
+					Data:      map[string]interface{}{"super_like": i%2 == 0},
+					CreatedAt: time.Now().UTC(),
+				}
+				require.NoError(t, repo.SaveNotification(notification))
+				ids = append(ids, notification.ID)
+			}
+			assert.Less(t, ids[0], ids[1])
+			assert.Less(t, ids[2], ids[3])
+
+			t.Run("Success - Newest First", func(t *testing.T) {
+				page := repo.GetNotificationsBefore(1, 0, 2)
+				require.Len(t, page, 2)
+				assert.Equal(t, []int{ids[3], ids[2]}, []int{page[0].ID, page[1].ID})
+				assert.Equal(t, models.EventLikeReceived, page[0].Type)
+				assert.Equal(t, false, page[0].Data["super_like"])
+
+				page = repo.GetNotificationsBefore(1, ids[2], 2)
+				require.Len(t, page, 1)
+				assert.Equal(t, ids[0], page[0].ID)
+				assert.Empty(t, repo.GetNotificationsBefore(3, 0, 2))
+			})
+
+			t.Run("Success - Mark Read", func(t *testing.T) {
+				assert.Equal(t, 3, repo.CountUnreadNotifications(1))
+				// Notifications of other users are left alone
+				assert.Equal(t, 1, repo.MarkNotificationsRead(1, []int{ids[0], ids[1]}))
+				assert.Equal(t, 0, repo.MarkNotificationsRead(1, []int{ids[0]}))
+				assert.Equal(t, 0, repo.MarkNotificationsRead(1, nil))
+				assert.Equal(t, 2, repo.CountUnreadNotifications(1))
+				assert.Equal(t, 1, repo.CountUnreadNotifications(2))
+
+				assert.Equal(t, 2, repo.MarkAllNotificationsRead(1))
+				assert.Equal(t, 0, repo.CountUnreadNotifications(1))
+				assert.Equal(t, 1, repo.CountUnreadNotifications(2))
+				assert.True(t, repo.GetNotificationsBefore(1, 0, 1)[0].Read)
+			})
+		})
+	}
+}
+
+func TestSQLiteNotificationRepositoryPersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	repo := repositories.NewNotificationRepositoryFor(newSQLiteUserRepository(t, path))
+	first := &models.Notification{UserID: 1, Type: models.EventMutualLike, CreatedAt: time.Now().UTC()}
+	require.NoError(t, repo.SaveNotification(first))
+
+	reopened := repositories.NewNotificationRepositoryFor(newSQLiteUserRepository(t, path))
+	second := &models.Notification{UserID: 1, Type: models.EventLikeReceived, CreatedAt: time.Now().UTC()}
+	require.NoError(t, reopened.SaveNotification(second))
+
+	notifications := reopened.GetNotificationsBefore(1, 0, 10)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, second.ID, notifications[0].ID)
+	assert.Equal(t, first.ID, notifications[1].ID)
+	assert.WithinDuration(t, first.CreatedAt, notifications[1].CreatedAt, time.Millisecond)
+}
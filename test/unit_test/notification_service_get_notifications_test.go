@@ -0,0 +1,130 @@
+package unit_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNotifications(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockNotificationRepo := new(userMock.MockNotificationRepository)
+	service := services.NewNotificationService(mockRepo, mockNotificationRepo)
+
+	notifications := func(ids ...int) []models.Notification {
+		result := []models.Notification{}
+		for _, id := range ids {
+			result = append(result, models.Notification{ID: id, UserID: 1, Type: models.EventLikeReceived})
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name           string
+		setupMocks     func()
+		expectedIDs    []int
+		expectedNext   bool
+		expectedUnread int
+		expectedError  string
+	}{
+		{
+			name: "Success - Last Page",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockNotificationRepo.On("GetNotificationsBefore", 1, 0, 3).Return(notifications(2, 1))
+				mockNotificationRepo.On("CountUnreadNotifications", 1).Return(1)
+			},
+			expectedIDs:    []int{2, 1},
+			expectedUnread: 1,
+		},
+		{
+			name: "Success - More Pages",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockNotificationRepo.On("GetNotificationsBefore", 1, 0, 3).Return(notifications(9, 8, 5))
+				mockNotificationRepo.On("CountUnreadNotifications", 1).Return(3)
+			},
+			expectedIDs:    []int{9, 8},
+			expectedNext:   true,
+			expectedUnread: 3,
+		},
+		{
+			name: "Error - User Not Found",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
+			},
+			expectedError: "user not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockNotificationRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			page, err := service.GetNotifications(1, "", 2)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				ids := []int{}
+				for _, notification := range page.Notifications {
+					ids = append(ids, notification.ID)
+				}
+				assert.Equal(t, tc.expectedIDs, ids)
+				assert.Equal(t, tc.expectedNext, page.NextCursor != "")
+				assert.Equal(t, tc.expectedUnread, page.UnreadCount)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, page)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockNotificationRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Next Cursor Continues Before The Oldest Notification", func(t *testing.T) {
+		mockRepo.ExpectedCalls = nil
+		mockNotificationRepo.ExpectedCalls = nil
+		mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+		mockNotificationRepo.On("GetNotificationsBefore", 1, 0, 3).Return(notifications(9, 8, 5)).Once()
+		mockNotificationRepo.On("GetNotificationsBefore", 1, 8, 3).Return(notifications(5)).Once()
+		mockNotificationRepo.On("CountUnreadNotifications", 1).Return(3)
+
+		page, err := service.GetNotifications(1, "", 2)
+		require.NoError(t, err)
+		page, err = service.GetNotifications(1, page.NextCursor, 2)
+		require.NoError(t, err)
+		assert.Len(t, page.Notifications, 1)
+		assert.Empty(t, page.NextCursor)
+
+		_, err = service.GetNotifications(1, base64.RawURLEncoding.EncodeToString([]byte(`{"s":true,"id":8}`)), 2)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+
+		mockNotificationRepo.AssertExpectations(t)
+	})
+}
+
+func TestNotificationServiceKeepsNotifiedEvents(t *testing.T) {
+	mockNotificationRepo := new(userMock.MockNotificationRepository)
+	service := services.NewNotificationService(new(userMock.MockUserRepository), mockNotificationRepo)
+
+	mockNotificationRepo.On("SaveNotification", mock.MatchedBy(func(notification *models.Notification) bool {
+		return notification.UserID == 2 && notification.Type == models.EventPremiumExpiring &&
+			!notification.Read && notification.Data["features"] != nil
+	})).Return(nil).Once()
+
+	service.Publish(models.Event{Type: models.EventPremiumExpiring, UserID: 2, Data: map[string]interface{}{"features": []string{"Rewind"}}})
+	// Quota resets are only pushed to live connections
+	service.Publish(models.Event{Type: models.EventQuotaReset, UserID: 2})
+
+	mockNotificationRepo.AssertExpectations(t)
+}
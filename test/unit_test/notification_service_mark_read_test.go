@@ -0,0 +1,87 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkNotificationsRead(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockNotificationRepo := new(userMock.MockNotificationRepository)
+	service := services.NewNotificationService(mockRepo, mockNotificationRepo)
+
+	testCases := []struct {
+		name           string
+		setupMocks     func()
+		ids            []int
+		expectedUnread int
+		expectedError  string
+	}{
+		{
+			name: "Success - Mark Some",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockNotificationRepo.On("MarkNotificationsRead", 1, []int{3, 4}).Return(2)
+				mockNotificationRepo.On("CountUnreadNotifications", 1).Return(5)
+			},
+			ids:            []int{3, 4},
+			expectedUnread: 5,
+		},
+		{
+			name:          "Error - No IDs",
+			setupMocks:    func() {},
+			ids:           []int{},
+			expectedError: services.ErrNoNotificationIDs.Error(),
+		},
+		{
+			name: "Error - User Not Found",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
+			},
+			ids:           []int{3},
+			expectedError: "user not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockNotificationRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			unread, err := service.MarkRead(1, tc.ids)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedUnread, unread)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockNotificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockNotificationRepo := new(userMock.MockNotificationRepository)
+	service := services.NewNotificationService(mockRepo, mockNotificationRepo)
+
+	mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+	mockNotificationRepo.On("MarkAllNotificationsRead", 1).Return(7)
+	mockNotificationRepo.On("CountUnreadNotifications", 1).Return(0)
+
+	unread, err := service.MarkAllRead(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, unread)
+
+	mockRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+}
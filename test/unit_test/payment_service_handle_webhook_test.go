@@ -0,0 +1,173 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleWebhook(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockPlanRepo := new(userMock.MockPlanRepository)
+	mockPurchaseRepo := new(userMock.MockPurchaseRepository)
+	mockProvider := new(userMock.MockPaymentProvider)
+	userService := services.NewUserService(mockRepo, mockPlanRepo, repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+	service := services.NewPaymentService(mockRepo, mockPlanRepo, mockPurchaseRepo, mockProvider, userService)
+
+	monthly := &models.Plan{ID: "monthly", DurationDays: 30, Price: 49000, Currency: "IDR", Features: []string{"UnlimitedSwipes"}}
+	succeeded := []byte(`{"type":"charge.succeeded","charge_id":"ch_1"}`)
+	failed := []byte(`{"type":"charge.failed","charge_id":"ch_1"}`)
+	pendingPurchase := func() *models.Purchase {
+		return &models.Purchase{ID: 1, UserID: 1, PlanID: "monthly", ChargeID: "ch_1", Status: models.PurchaseStatusPending}
+	}
+	withStatus := func(status string) interface{} {
+		return mock.MatchedBy(func(purchase *models.Purchase) bool { return purchase.Status == status })
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		payload       []byte
+		expectedError string
+	}{
+		{
+			name: "Success - Signed Success Callback Activates Premium",
+			setupMocks: func() {
+				mockProvider.On("VerifyWebhookSignature", succeeded, "sig").Return(nil)
+				mockPurchaseRepo.On("GetPurchaseByChargeID", "ch_1").Return(pendingPurchase(), nil)
+				mockPurchaseRepo.On("GetPurchaseByID", 1).Return(pendingPurchase(), nil)
+				mockProvider.On("CaptureCharge", "ch_1").Return(&payments.Charge{ID: "ch_1", Status: payments.ChargeStatusCaptured}, nil)
+				mockPlanRepo.On("GetPlanByID", "monthly").Return(monthly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.PremiumFeatures.UnlimitedSwipes
+				})).Return(nil)
+				mockPurchaseRepo.On("UpdatePurchase", withStatus(models.PurchaseStatusPaid)).Return(nil)
+			},
+			payload: succeeded,
+		},
+		{
+			name: "Error - Invalid Signature",
+			setupMocks: func() {
+				mockProvider.On("VerifyWebhookSignature", succeeded, "sig").Return(payments.ErrInvalidSignature)
+			},
+			payload:       succeeded,
+			expectedError: "invalid webhook signature",
+		},
+		{
+			name: "Success - Duplicate Callback Is Ignored",
+			setupMocks: func() {
+				paid := pendingPurchase()
+				paid.Status = models.PurchaseStatusPaid
+				mockProvider.On("VerifyWebhookSignature", succeeded, "sig").Return(nil)
+				mockPurchaseRepo.On("GetPurchaseByChargeID", "ch_1").Return(paid, nil)
+				mockPurchaseRepo.On("GetPurchaseByID", 1).Return(paid, nil)
+			},
+			payload: succeeded,
+		},
+		{
+			name: "Success - Failed Payment Marks Purchase Failed",
+			setupMocks: func() {
+				mockProvider.On("VerifyWebhookSignature", failed, "sig").Return(nil)
+				mockPurchaseRepo.On("GetPurchaseByChargeID", "ch_1").Return(pendingPurchase(), nil)
+				mockPurchaseRepo.On("GetPurchaseByID", 1).Return(pendingPurchase(), nil)
+				mockPurchaseRepo.On("UpdatePurchase", withStatus(models.PurchaseStatusFailed)).Return(nil)
+			},
+			payload: failed,
+		},
+		{
+			name: "Success - Premium Rejected Refunds Charge",
+			setupMocks: func() {
+				mockProvider.On("VerifyWebhookSignature", succeeded, "sig").Return(nil)
+				mockPurchaseRepo.On("GetPurchaseByChargeID", "ch_1").Return(pendingPurchase(), nil)
+				mockPurchaseRepo.On("GetPurchaseByID", 1).Return(pendingPurchase(), nil)
+				mockProvider.On("CaptureCharge", "ch_1").Return(&payments.Charge{ID: "ch_1", Status: payments.ChargeStatusCaptured}, nil)
+				mockPlanRepo.On("GetPlanByID", "monthly").Return(monthly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, IsInactive: true}, nil)
+				mockProvider.On("RefundCharge", "ch_1").Return(&payments.Charge{ID: "ch_1", Status: payments.ChargeStatusRefunded}, nil)
+				mockPurchaseRepo.On("UpdatePurchase", withStatus(models.PurchaseStatusRefunded)).Return(nil)
+			},
+			payload: succeeded,
+		},
+		{
+			name: "Success - Premium Not Saved Refunds Charge",
+			setupMocks: func() {
+				mockProvider.On("VerifyWebhookSignature", succeeded, "sig").Return(nil)
+				mockPurchaseRepo.On("GetPurchaseByChargeID", "ch_1").Return(pendingPurchase(), nil)
+				mockPurchaseRepo.On("GetPurchaseByID", 1).Return(pendingPurchase(), nil)
+				mockProvider.On("CaptureCharge", "ch_1").Return(&payments.Charge{ID: "ch_1", Status: payments.ChargeStatusCaptured}, nil)
+				mockPlanRepo.On("GetPlanByID", "monthly").Return(monthly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockRepo.On("UpdateUser", mock.AnythingOfType("*models.User")).Return(errors.New("disk full"))
+				mockProvider.On("RefundCharge", "ch_1").Return(&payments.Charge{ID: "ch_1", Status: payments.ChargeStatusRefunded}, nil)
+				mockPurchaseRepo.On("UpdatePurchase", withStatus(models.PurchaseStatusRefunded)).Return(nil)
+			},
+			payload: succeeded,
+		},
+		{
+			name: "Error - Unknown Charge",
+			setupMocks: func() {
+				mockProvider.On("VerifyWebhookSignature", succeeded, "sig").Return(nil)
+				mockPurchaseRepo.On("GetPurchaseByChargeID", "ch_1").Return(nil, errors.New("purchase not found"))
+			},
+			payload:       succeeded,
+			expectedError: "purchase not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockPlanRepo.ExpectedCalls = nil
+			mockPurchaseRepo.ExpectedCalls = nil
+			mockProvider.ExpectedCalls = nil
+			tc.setupMocks()
+
+			err := service.HandleWebhook(tc.payload, "sig")
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockPlanRepo.AssertExpectations(t)
+			mockPurchaseRepo.AssertExpectations(t)
+			mockProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider := payments.NewFakeProvider("secret")
+	payload := []byte(`{"type":"charge.succeeded","charge_id":"ch_fake_1"}`)
+
+	assert.NoError(t, provider.VerifyWebhookSignature(payload, provider.SignPayload(payload)))
+	assert.ErrorIs(t, provider.VerifyWebhookSignature(payload, "deadbeef"), payments.ErrInvalidSignature)
+	assert.ErrorIs(t, payments.NewFakeProvider("other").VerifyWebhookSignature(payload, provider.SignPayload(payload)), payments.ErrInvalidSignature)
+	assert.ErrorIs(t, payments.NewFakeProvider("").VerifyWebhookSignature(payload, payments.NewFakeProvider("").SignPayload(payload)), payments.ErrInvalidSignature)
+
+	charge, err := provider.CreateCharge(49000, "IDR", "purchase-1")
+	assert.NoError(t, err)
+	assert.Equal(t, payments.ChargeStatusPending, charge.Status)
+
+	_, err = provider.RefundCharge(charge.ID)
+	assert.Error(t, err, "a pending charge can't be refunded")
+
+	captured, err := provider.CaptureCharge(charge.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, payments.ChargeStatusCaptured, captured.Status)
+
+	refunded, err := provider.RefundCharge(charge.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, payments.ChargeStatusRefunded, refunded.Status)
+}
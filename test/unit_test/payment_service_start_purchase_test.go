@@ -0,0 +1,97 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/payments"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStartPurchase(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockPlanRepo := new(userMock.MockPlanRepository)
+	mockPurchaseRepo := new(userMock.MockPurchaseRepository)
+	mockProvider := new(userMock.MockPaymentProvider)
+	service := services.NewPaymentService(mockRepo, mockPlanRepo, mockPurchaseRepo, mockProvider, nil)
+
+	monthly := &models.Plan{ID: "monthly", DurationDays: 30, Price: 49000, Currency: "IDR", Features: []string{"UnlimitedSwipes"}}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		planID        string
+		expectedError string
+	}{
+		{
+			name: "Success - Pending Purchase Created",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockPlanRepo.On("GetPlanByID", "monthly").Return(monthly, nil)
+				mockPurchaseRepo.On("GeneratePurchaseID").Return(7)
+				mockProvider.On("CreateCharge", int64(49000), "IDR", "purchase-7").Return(&payments.Charge{ID: "ch_1", Status: payments.ChargeStatusPending}, nil)
+				mockPurchaseRepo.On("SavePurchase", mock.MatchedBy(func(purchase *models.Purchase) bool {
+					return purchase.ID == 7 && purchase.ChargeID == "ch_1" && purchase.Status == models.PurchaseStatusPending
+				})).Return(nil)
+			},
+			planID: "monthly",
+		},
+		{
+			name: "Error - Unknown Plan",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockPlanRepo.On("GetPlanByID", "lifetime").Return(nil, errors.New("plan not found"))
+			},
+			planID:        "lifetime",
+			expectedError: "plan not found",
+		},
+		{
+			name: "Error - Inactive User",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, IsInactive: true}, nil)
+			},
+			planID:        "monthly",
+			expectedError: "user account is inactive",
+		},
+		{
+			name: "Error - Provider Unavailable",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockPlanRepo.On("GetPlanByID", "monthly").Return(monthly, nil)
+				mockPurchaseRepo.On("GeneratePurchaseID").Return(8)
+				mockProvider.On("CreateCharge", int64(49000), "IDR", "purchase-8").Return(nil, errors.New("timeout"))
+			},
+			planID:        "monthly",
+			expectedError: "failed to create payment",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockPlanRepo.ExpectedCalls = nil
+			mockPurchaseRepo.ExpectedCalls = nil
+			mockProvider.ExpectedCalls = nil
+			tc.setupMocks()
+
+			purchase, err := service.StartPurchase(1, tc.planID)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, models.PurchaseStatusPending, purchase.Status)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockPlanRepo.AssertExpectations(t)
+			mockPurchaseRepo.AssertExpectations(t)
+			mockProvider.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package unit_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchaseRepository(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := repositories.NewPurchaseRepositoryFor(newRepo())
+
+			now := time.Now().UTC()
+			purchase := &models.Purchase{
+				ID:        repo.GeneratePurchaseID(),
+				UserID:    1,
+				PlanID:    "monthly",
+				ChargeID:  "ch_1",
+				Amount:    49000,
+				Currency:  "IDR",
+				Status:    models.PurchaseStatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			require.NoError(t, repo.SavePurchase(purchase))
+			assert.EqualError(t, repo.SavePurchase(purchase), "purchase ID already exists")
+			assert.Less(t, purchase.ID, repo.GeneratePurchaseID())
+
+			t.Run("Success - Lookups", func(t *testing.T) {
+				saved, err := repo.GetPurchaseByChargeID("ch_1")
+				require.NoError(t, err)
+				assert.Equal(t, purchase.ID, saved.ID)
+				assert.Equal(t, "monthly", saved.PlanID)
+				assert.Equal(t, int64(49000), saved.Amount)
+				assert.Equal(t, models.PurchaseStatusPending, saved.Status)
+				assert.WithinDuration(t, now, saved.CreatedAt, time.Second)
+
+				_, err = repo.GetPurchaseByChargeID("ch_2")
+				assert.EqualError(t, err, "purchase not found")
+				_, err = repo.GetPurchaseByID(purchase.ID + 100)
+				assert.EqualError(t, err, "purchase not found")
+			})
+
+			t.Run("Success - Update", func(t *testing.T) {
+				purchase.Status = models.PurchaseStatusFailed
+				purchase.FailureReason = "card declined"
+				require.NoError(t, repo.UpdatePurchase(purchase))
+
+				saved, err := repo.GetPurchaseByID(purchase.ID)
+				require.NoError(t, err)
+				assert.Equal(t, models.PurchaseStatusFailed, saved.Status)
+				assert.Equal(t, "card declined", saved.FailureReason)
+
+				assert.EqualError(t, repo.UpdatePurchase(&models.Purchase{ID: purchase.ID + 100}), "purchase not found")
+			})
+		})
+	}
+}
+
+func TestSQLitePurchasesSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	repo := repositories.NewPurchaseRepositoryFor(newSQLiteUserRepository(t, path))
+	purchase := &models.Purchase{ID: repo.GeneratePurchaseID(), UserID: 1, PlanID: "monthly", ChargeID: "ch_1", Status: models.PurchaseStatusPending, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, repo.SavePurchase(purchase))
+
+	reopened := repositories.NewPurchaseRepositoryFor(newSQLiteUserRepository(t, path))
+	saved, err := reopened.GetPurchaseByChargeID("ch_1")
+	require.NoError(t, err)
+	assert.Equal(t, purchase.ID, saved.ID)
+	assert.Equal(t, purchase.ID+1, reopened.GeneratePurchaseID())
+}
@@ -0,0 +1,171 @@
+package unit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScorers(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	viewer := &models.User{ID: 1, Interests: []string{"hiking", "jazz", "coffee"}}
+	halfway := 10.0
+
+	testCases := []struct {
+		name     string
+		scorer   services.ScorerFunc
+		ctx      services.ScoringContext
+		expected float64
+	}{
+		{
+			name:     "Recency - Just Swiped",
+			scorer:   services.RecencyScore,
+			ctx:      services.ScoringContext{Candidate: &models.User{}, Activity: models.SwipeActivity{LastSwipeAt: now}, Now: now},
+			expected: 1,
+		},
+		{
+			name:     "Recency - Halves Every Three Days",
+			scorer:   services.RecencyScore,
+			ctx:      services.ScoringContext{Candidate: &models.User{}, Activity: models.SwipeActivity{LastSwipeAt: now.Add(-6 * 24 * time.Hour)}, Now: now},
+			expected: 0.25,
+		},
+		{
+			name:     "Recency - Falls Back To Sign Up Time",
+			scorer:   services.RecencyScore,
+			ctx:      services.ScoringContext{Candidate: &models.User{CreatedAt: now.Add(-3 * 24 * time.Hour)}, Now: now},
+			expected: 0.5,
+		},
+		{
+			name:     "Completeness - Empty Profile",
+			scorer:   services.CompletenessScore,
+			ctx:      services.ScoringContext{Candidate: &models.User{}},
+			expected: 0,
+		},
+		{
+			name:   "Completeness - Partial Profile",
+			scorer: services.CompletenessScore,
+			ctx: services.ScoringContext{Candidate: &models.User{
+				Name: "User", Bio: "Hello", Interests: []string{"jazz"}, Occupation: " ",
+			}},
+			expected: 0.6,
+		},
+		{
+			name:     "Interest Overlap - Shared Interests",
+			scorer:   services.InterestOverlapScore,
+			ctx:      services.ScoringContext{Viewer: viewer, Candidate: &models.User{Interests: []string{"jazz", "coffee", "chess"}}},
+			expected: 0.5,
+		},
+		{
+			name:     "Interest Overlap - Nothing To Compare",
+			scorer:   services.InterestOverlapScore,
+			ctx:      services.ScoringContext{Viewer: &models.User{}, Candidate: &models.User{}},
+			expected: 0,
+		},
+		{
+			name:     "Desirability - No Swipes Yet",
+			scorer:   services.DesirabilityScore,
+			ctx:      services.ScoringContext{},
+			expected: 0.5,
+		},
+		{
+			name:     "Proximity - Halfway To The Maximum Distance",
+			scorer:   services.ProximityScore,
+			ctx:      services.ScoringContext{Viewer: &models.User{Preferences: models.Preferences{MaxDistanceKm: 20}}, DistanceKm: &halfway},
+			expected: 0.5,
+		},
+		{
+			name:     "Proximity - Without Maximum Distance",
+			scorer:   services.ProximityScore,
+			ctx:      services.ScoringContext{Viewer: &models.User{}, DistanceKm: &halfway},
+			expected: 0,
+		},
+		{
+			name:     "Proximity - Without Location",
+			scorer:   services.ProximityScore,
+			ctx:      services.ScoringContext{Viewer: &models.User{Preferences: models.Preferences{MaxDistanceKm: 20}}},
+			expected: 0,
+		},
+		{
+			name:     "Desirability - Mostly Liked",
+			scorer:   services.DesirabilityScore,
+			ctx:      services.ScoringContext{Activity: models.SwipeActivity{LikesReceived: 25, SwipesReceived: 30}},
+			expected: 0.75,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, tc.scorer.Score(tc.ctx), 1e-9)
+		})
+	}
+}
+
+func TestRanker(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	viewer := &models.User{ID: 1, Interests: []string{"hiking", "jazz"}}
+
+	candidates := func() []models.Candidate {
+		return []models.Candidate{
+			{User: models.User{ID: 2}},
+			{User: models.User{ID: 3, Interests: []string{"hiking", "jazz"}}},
+			{User: models.User{ID: 4, Name: "Four", Bio: "Hi", Occupation: "Chef", Interests: []string{"chess"}, Birthdate: models.NewDate(1995, time.April, 23)}},
+			{User: models.User{ID: 5}},
+		}
+	}
+	activity := map[int]models.SwipeActivity{
+		5: {LikesReceived: 40, SwipesReceived: 40, LastSwipeAt: now},
+	}
+	ids := func(ranked []models.Candidate) []int {
+		var result []int
+		for _, candidate := range ranked {
+			result = append(result, candidate.User.ID)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name        string
+		ranker      *services.Ranker
+		expectedIDs []int
+	}{
+		{
+			name:        "Interest Overlap Only",
+			ranker:      services.NewRankerWithWeights(services.RankingWeights{InterestOverlap: 1}),
+			expectedIDs: []int{3, 2, 4, 5},
+		},
+		{
+			name:        "Completeness Only",
+			ranker:      services.NewRankerWithWeights(services.RankingWeights{Completeness: 1}),
+			expectedIDs: []int{4, 3, 2, 5},
+		},
+		{
+			name:        "Recency And Desirability",
+			ranker:      services.NewRankerWithWeights(services.RankingWeights{Recency: 1, Desirability: 1}),
+			expectedIDs: []int{5, 2, 3, 4},
+		},
+		{
+			name:        "No Weights Keeps The Original Order",
+			ranker:      services.NewRankerWithWeights(services.RankingWeights{}),
+			expectedIDs: []int{2, 3, 4, 5},
+		},
+		{
+			name: "Custom Scorer",
+			ranker: services.NewRanker(services.WeightedScorer{
+				Scorer: services.ScorerFunc(func(ctx services.ScoringContext) float64 { return float64(ctx.Candidate.ID) }),
+				Weight: 1,
+			}),
+			expectedIDs: []int{5, 4, 3, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranked := candidates()
+			tc.ranker.Rank(viewer, ranked, activity, now)
+			assert.Equal(t, tc.expectedIDs, ids(ranked))
+		})
+	}
+}
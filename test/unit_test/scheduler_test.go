@@ -0,0 +1,83 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/scheduler"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type countingJob struct {
+	runs int32
+	err  error
+}
+
+func (j *countingJob) Name() string { return "counting" }
+
+func (j *countingJob) Run(now time.Time) error {
+	atomic.AddInt32(&j.runs, 1)
+	return j.err
+}
+
+func TestSchedulerRunsJobsUntilStopped(t *testing.T) {
+	job := &countingJob{}
+	failingJob := &countingJob{err: errors.New("boom")}
+
+	s := scheduler.NewScheduler()
+	s.Every(10*time.Millisecond, job)
+	s.Every(10*time.Millisecond, failingJob)
+	s.Start(context.Background())
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&job.runs) >= 3 }, time.Second, 5*time.Millisecond)
+	// A failing job keeps being scheduled
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&failingJob.runs) >= 3 }, time.Second, 5*time.Millisecond)
+
+	s.Stop()
+	runs := atomic.LoadInt32(&job.runs)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, atomic.LoadInt32(&job.runs), "no runs after Stop")
+}
+
+func TestQuotaResetJobNotifiesOncePerDay(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	publisher := new(userMock.MockEventPublisher)
+	swipeService := services.NewSwipeServiceWith(mockRepo, repositories.NewMatchRepository(), services.NewRankerWithWeights(services.DefaultRankingWeights), publisher)
+	job := scheduler.NewQuotaResetJob(swipeService)
+
+	// Starting up isn't a reset, neither is another tick on the same day
+	day := time.Date(2024, time.May, 1, 23, 58, 0, 0, time.UTC)
+	assert.NoError(t, job.Run(day))
+	assert.NoError(t, job.Run(day.Add(time.Minute)))
+	mockRepo.AssertNotCalled(t, "GetAllUsers")
+
+	mockRepo.On("GetAllUsers").Return([]*models.User{{ID: 1}}).Once()
+	mockRepo.On("GetSwipeActivity", []int{1}, mock.Anything).Return(map[int]models.SwipeActivity{1: {LastSwipeAt: day}}, nil).Once()
+	publisher.On("Publish", mock.MatchedBy(func(event models.Event) bool { return event.Type == models.EventQuotaReset })).Once()
+	assert.NoError(t, job.Run(day.Add(2*time.Minute)))
+	assert.NoError(t, job.Run(day.Add(3*time.Minute)))
+
+	mockRepo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestQuotaResetJobReportsRepositoryErrors(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	swipeService := services.NewSwipeServiceWith(mockRepo, repositories.NewMatchRepository(), services.NewRankerWithWeights(services.DefaultRankingWeights), new(userMock.MockEventPublisher))
+	job := scheduler.NewQuotaResetJob(swipeService)
+
+	day := time.Date(2024, time.May, 1, 23, 58, 0, 0, time.UTC)
+	assert.NoError(t, job.Run(day))
+
+	mockRepo.On("GetAllUsers").Return([]*models.User{{ID: 1}})
+	mockRepo.On("GetSwipeActivity", []int{1}, mock.Anything).Return(nil, errors.New("database is locked"))
+	assert.EqualError(t, job.Run(day.Add(2*time.Minute)), "database is locked")
+}
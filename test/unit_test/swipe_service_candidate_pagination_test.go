@@ -0,0 +1,241 @@
+package unit_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSwipeCandidatesPagination(t *testing.T) {
+	center := models.Location{Latitude: -6.2, Longitude: 106.8}
+
+	testCases := []struct {
+		name        string
+		preferences models.Preferences
+		boosted     []int
+		limit       int
+		expected    int
+	}{
+		{name: "By ID - Spans Several Repository Batches", limit: 7, expected: 125},
+		{name: "By ID - Single Page", limit: 50, expected: 125},
+		{name: "By Distance", preferences: models.Preferences{InterestedIn: []string{"female"}, MaxDistanceKm: 100}, limit: 6, expected: 125},
+		{name: "By ID - Boosted Profiles First", boosted: []int{200, 41, 120, 36}, limit: 2, expected: 125},
+		{name: "By Distance - Boosted Profiles First", preferences: models.Preferences{InterestedIn: []string{"female"}, MaxDistanceKm: 100}, boosted: []int{200, 41, 120, 36}, limit: 7, expected: 125},
+	}
+
+	for name, newRepo := range userRepositoryFactories(t) {
+		for _, tc := range testCases {
+			t.Run(name+" - "+tc.name, func(t *testing.T) {
+				repo := newRepo()
+				service := services.NewSwipeService(repo, repositories.NewMatchRepositoryFor(repo))
+
+				require.NoError(t, repo.SaveUser(&models.User{ID: 1, Email: "me@example.com", Phone: "me", Gender: "male", Location: &center, Preferences: tc.preferences}))
+				// Every other user is female, the males must be skipped without shortening pages
+				for id := 2; id <= 251; id++ {
+					gender := "male"
+					if id%2 == 0 {
+						gender = "female"
+					}
+					// Several users share a distance so the ID has to break ties
+					location := models.Location{Latitude: center.Latitude + float64(id%10)*0.05, Longitude: center.Longitude}
+					user := &models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id), Gender: gender, Location: &location}
+					// Profiles are more or less complete so the ranking has to order them across pages
+					if id%3 == 0 {
+						user.Bio = "bio"
+					}
+					if id%7 == 0 {
+						user.Occupation = "occupation"
+					}
+					require.NoError(t, repo.SaveUser(user))
+				}
+				boostedUntil := time.Now().Add(time.Hour)
+				for _, id := range tc.boosted {
+					user, err := repo.GetUserByID(id)
+					require.NoError(t, err)
+					user.BoostedUntil = &boostedUntil
+					require.NoError(t, repo.UpdateUser(user))
+				}
+
+				seen := map[int]bool{}
+				var ordered []models.Candidate
+				cursor := ""
+				for pages := 0; ; pages++ {
+					require.Less(t, pages, 100, "pagination doesn't terminate")
+					page, err := service.GetSwipeCandidates(1, cursor, tc.limit)
+					require.NoError(t, err)
+					assert.LessOrEqual(t, len(page.Candidates), tc.limit)
+
+					for _, candidate := range page.Candidates {
+						assert.False(t, seen[candidate.User.ID], "user %d returned twice", candidate.User.ID)
+						seen[candidate.User.ID] = true
+						assert.Equal(t, "female", candidate.User.Gender)
+						ordered = append(ordered, candidate)
+					}
+					if page.NextCursor == "" {
+						break
+					}
+					cursor = page.NextCursor
+				}
+				assert.Len(t, seen, tc.expected)
+
+				byID := func(a, b models.Candidate) bool { return a.User.ID < b.User.ID }
+				// Nearer profiles are read first when there is a maximum distance, IDs break ties
+				deckOrder := func(a, b models.Candidate) bool {
+					if tc.preferences.MaxDistanceKm > 0 && *a.DistanceKm != *b.DistanceKm {
+						return *a.DistanceKm < *b.DistanceKm
+					}
+					return byID(a, b)
+				}
+
+				// The boosted females lead the deck, the male one is still filtered out
+				if len(tc.boosted) > 0 {
+					var leading []int
+					for _, candidate := range ordered[:3] {
+						leading = append(leading, candidate.User.ID)
+					}
+					assert.ElementsMatch(t, []int{36, 120, 200}, leading)
+					assertRanked(t, ordered[:3], byID)
+					ordered = ordered[3:]
+				}
+
+				// The rest is ranked in windows that follow each other in deck order
+				for start := 0; start < len(ordered); start += services.CandidateRankWindow {
+					end := min(start+services.CandidateRankWindow, len(ordered))
+					window := ordered[start:end]
+					assertRanked(t, window, deckOrder)
+					if end == len(ordered) {
+						break
+					}
+					latest := window[0]
+					for _, candidate := range window {
+						if deckOrder(latest, candidate) {
+							latest = candidate
+						}
+					}
+					for _, candidate := range ordered[end:] {
+						assert.True(t, deckOrder(latest, candidate), "user %d read before user %d", candidate.User.ID, latest.User.ID)
+					}
+				}
+				assert.Greater(t, ordered[0].Score, ordered[min(services.CandidateRankWindow, len(ordered))-1].Score)
+				for _, candidate := range ordered {
+					if tc.preferences.MaxDistanceKm > 0 {
+						assert.LessOrEqual(t, *candidate.DistanceKm, float64(tc.preferences.MaxDistanceKm))
+					}
+				}
+			})
+		}
+	}
+}
+
+// assertRanked checks that the candidates are ordered best first, equal scores in deck order
+func assertRanked(t *testing.T, candidates []models.Candidate, deckOrder func(a, b models.Candidate) bool) {
+	t.Helper()
+	for i := 1; i < len(candidates); i++ {
+		previous, current := candidates[i-1], candidates[i]
+		assert.True(t, previous.Score > current.Score || (previous.Score == current.Score && deckOrder(previous, current)),
+			"user %d (%v) ranked before user %d (%v)", previous.User.ID, previous.Score, current.User.ID, current.Score)
+	}
+}
+
+func TestGetSwipeCandidatesInvalidCursor(t *testing.T) {
+	repo := repositories.NewUserRepository()
+	service := services.NewSwipeService(repo, repositories.NewMatchRepositoryFor(repo))
+	require.NoError(t, repo.SaveUser(&models.User{ID: 1, Email: "me@example.com", Phone: "me", Gender: "male"}))
+
+	// Garbage, a message cursor, a cursor from before decks were ranked as a whole and an unknown segment
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", "eyJpZCI6M30", "eyJiIjp0cnVlLCJpZCI6M30", "eyJnIjozLCJyIjowLjUsImlkIjozLCJ0IjoxfQ"} {
+		_, err := service.GetSwipeCandidates(1, cursor, 10)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	}
+}
+
+func TestGetSwipeCandidatesSuperLikesFirst(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			service := services.NewSwipeService(repo, repositories.NewMatchRepositoryFor(repo))
+
+			require.NoError(t, repo.SaveUser(&models.User{ID: 1, Email: "me@example.com", Phone: "me", Gender: "male"}))
+			boostedUntil := time.Now().Add(time.Hour)
+			for id := 2; id <= 6; id++ {
+				user := &models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id), Gender: "female"}
+				if id == 3 {
+					user.BoostedUntil = &boostedUntil
+				}
+				require.NoError(t, repo.SaveUser(user))
+			}
+
+			// User 4 was liked yesterday, her super like makes a match and there is nothing left to answer
+			require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 1, TargetUserID: 4, Action: "like", CreatedAt: time.Now().Add(-24 * time.Hour)}))
+			for _, id := range []int{6, 4, 5} {
+				_, err := service.RecordSwipe(&models.Swipe{UserID: id, TargetUserID: 1, Action: "super_like"})
+				require.NoError(t, err)
+			}
+
+			var ids []int
+			var superLiked []int
+			cursor := ""
+			for pages := 0; pages < 10; pages++ {
+				page, err := service.GetSwipeCandidates(1, cursor, 2)
+				require.NoError(t, err)
+				for _, candidate := range page.Candidates {
+					ids = append(ids, candidate.User.ID)
+					if candidate.SuperLikedYou {
+						superLiked = append(superLiked, candidate.User.ID)
+					}
+				}
+				if cursor = page.NextCursor; cursor == "" {
+					break
+				}
+			}
+
+			// User 4 has just been active, the ranking puts her ahead of user 2 although they are on different pages
+			assert.Equal(t, []int{5, 6, 3, 4, 2}, ids)
+			assert.Equal(t, []int{5, 6}, superLiked)
+		})
+	}
+}
+
+func TestGetSwipeCandidatesScoresStayPinned(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			service := services.NewSwipeService(repo, repositories.NewMatchRepositoryFor(repo))
+
+			require.NoError(t, repo.SaveUser(&models.User{ID: 1, Email: "me@example.com", Phone: "me", Gender: "male"}))
+			for id := 2; id <= 3; id++ {
+				require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id), Gender: "female"}))
+			}
+			for id := 10; id <= 20; id++ {
+				require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id), Gender: "male"}))
+			}
+
+			page, err := service.GetSwipeCandidates(1, "", 1)
+			require.NoError(t, err)
+			require.Len(t, page.Candidates, 1)
+			assert.Equal(t, 2, page.Candidates[0].User.ID)
+
+			// User 3 becomes active and popular after the first page was ranked, and the user swipes
+			// on the profile they were shown. Neither may cost user 3 her place on the next page.
+			time.Sleep(2 * time.Millisecond)
+			for id := 10; id <= 20; id++ {
+				require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: id, TargetUserID: 3, Action: "like", CreatedAt: time.Now()}))
+			}
+			require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 3, TargetUserID: 10, Action: "like", CreatedAt: time.Now()}))
+			_, err = service.RecordSwipe(&models.Swipe{UserID: 1, TargetUserID: 2, Action: "pass"})
+			require.NoError(t, err)
+
+			page, err = service.GetSwipeCandidates(1, page.NextCursor, 1)
+			require.NoError(t, err)
+			require.Len(t, page.Candidates, 1)
+			assert.Equal(t, 3, page.Candidates[0].User.ID)
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+}
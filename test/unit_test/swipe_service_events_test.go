@@ -0,0 +1,74 @@
+package unit_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwipeServicePublishesEvents(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			for id := 1; id <= 3; id++ {
+				require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("test%d@example.com", id), Phone: fmt.Sprint(id)}))
+			}
+			publisher := new(userMock.MockEventPublisher)
+			service := services.NewSwipeServiceWith(repo, repositories.NewMatchRepository(), services.NewRankerWithWeights(services.DefaultRankingWeights), publisher)
+
+			event := func(eventType string, userID int) interface{} {
+				return mock.MatchedBy(func(event models.Event) bool { return event.Type == eventType && event.UserID == userID })
+			}
+
+			// A pass goes unnoticed, a like only tells that someone liked
+			_, err := service.RecordSwipe(&models.Swipe{UserID: 1, TargetUserID: 3, Action: "pass"})
+			require.NoError(t, err)
+			publisher.On("Publish", mock.MatchedBy(func(event models.Event) bool {
+				return event.Type == models.EventLikeReceived && event.UserID == 2 && event.Data["super_like"] == true && event.Data["user_id"] == nil
+			})).Once()
+			_, err = service.RecordSwipe(&models.Swipe{UserID: 1, TargetUserID: 2, Action: "super_like"})
+			require.NoError(t, err)
+			publisher.AssertExpectations(t)
+
+			// Liking back tells both users about the match instead
+			publisher.ExpectedCalls = nil
+			publisher.On("Publish", event(models.EventMutualLike, 1)).Once()
+			publisher.On("Publish", event(models.EventMutualLike, 2)).Once()
+			match, err := service.RecordSwipe(&models.Swipe{UserID: 2, TargetUserID: 1, Action: "like"})
+			require.NoError(t, err)
+			require.NotNil(t, match)
+			publisher.AssertExpectations(t)
+			publisher.AssertNumberOfCalls(t, "Publish", 3)
+		})
+	}
+}
+
+func TestNotifyQuotaReset(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	publisher := new(userMock.MockEventPublisher)
+	service := services.NewSwipeServiceWith(mockRepo, repositories.NewMatchRepository(), services.NewRankerWithWeights(services.DefaultRankingWeights), publisher)
+
+	today := time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetAllUsers").Return([]*models.User{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4, IsInactive: true}})
+	mockRepo.On("GetSwipeActivity", []int{1, 2, 3}, today).Return(map[int]models.SwipeActivity{
+		1: {LastSwipeAt: today.Add(-time.Hour)},
+		2: {LastSwipeAt: today.Add(-25 * time.Hour)},
+		3: {SwipesReceived: 4},
+	}, nil)
+	publisher.On("Publish", models.Event{Type: models.EventQuotaReset, UserID: 1, CreatedAt: today}).Once()
+
+	notified, err := service.NotifyQuotaReset(today.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, notified)
+
+	mockRepo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
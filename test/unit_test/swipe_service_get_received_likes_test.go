@@ -0,0 +1,133 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReceivedLikes(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	service := services.NewSwipeService(mockRepo, repositories.NewMatchRepository())
+
+	premium := &models.User{ID: 1, PremiumExpiry: utils.TimePtr(time.Now().Add(24 * time.Hour))}
+	expired := &models.User{ID: 1, PremiumExpiry: utils.TimePtr(time.Now().Add(-time.Hour))}
+	hourAgo := time.Now().Add(-time.Hour)
+
+	received := []models.Swipe{
+		{ID: 1, UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: hourAgo},
+		{ID: 2, UserID: 3, TargetUserID: 1, Action: "super_like", CreatedAt: hourAgo.Add(time.Minute)},
+		{ID: 3, UserID: 4, TargetUserID: 1, Action: "pass", CreatedAt: hourAgo.Add(2 * time.Minute)},
+	}
+	likers := func() {
+		mockRepo.On("GetUserByID", 2).Return(&models.User{ID: 2, Name: "Two"}, nil)
+		mockRepo.On("GetUserByID", 3).Return(&models.User{ID: 3, Name: "Three"}, nil)
+	}
+
+	testCases := []struct {
+		name            string
+		setupMocks      func()
+		expectedLikers  []int
+		expectedSuper   []bool
+		expectedVisible bool
+		expectedError   string
+	}{
+		{
+			name: "Success - Premium User Sees Likers Newest First",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipesReceived", 1).Return(received)
+				likers()
+			},
+			expectedLikers:  []int{3, 2},
+			expectedSuper:   []bool{true, false},
+			expectedVisible: true,
+		},
+		{
+			name: "Success - Free User Gets Blurred Likes",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(expired, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipesReceived", 1).Return(received)
+				likers()
+			},
+			expectedLikers: []int{0, 0},
+			expectedSuper:  []bool{true, false},
+		},
+		{
+			name: "Success - Answered And Inactive Likers Are Left Out",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
+					{ID: 4, UserID: 1, TargetUserID: 2, Action: "pass", CreatedAt: hourAgo.Add(time.Second)},
+				})
+				mockRepo.On("GetSwipesReceived", 1).Return(received)
+				mockRepo.On("GetUserByID", 3).Return(&models.User{ID: 3, IsInactive: true}, nil)
+			},
+			expectedLikers:  []int{},
+			expectedSuper:   []bool{},
+			expectedVisible: true,
+		},
+		{
+			name: "Success - Liked Again After A Pass",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
+					{ID: 4, UserID: 1, TargetUserID: 2, Action: "pass", CreatedAt: hourAgo.Add(-24 * time.Hour)},
+					{ID: 5, UserID: 1, TargetUserID: 3, Action: "like", CreatedAt: hourAgo.Add(-24 * time.Hour)},
+				})
+				mockRepo.On("GetSwipesReceived", 1).Return(received)
+				likers()
+			},
+			expectedLikers:  []int{2},
+			expectedSuper:   []bool{false},
+			expectedVisible: true,
+		},
+		{
+			name: "Error - User Not Found",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
+			},
+			expectedError: "user not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			likes, err := service.GetReceivedLikes(1)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, likes)
+				mockRepo.AssertExpectations(t)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedVisible, likes.Visible)
+			likerIDs, superLikes := []int{}, []bool{}
+			for _, like := range likes.Likes {
+				id := 0
+				if like.User != nil {
+					id = like.User.ID
+				}
+				likerIDs = append(likerIDs, id)
+				superLikes = append(superLikes, like.SuperLike)
+			}
+			assert.Equal(t, tc.expectedLikers, likerIDs)
+			assert.Equal(t, tc.expectedSuper, superLikes)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
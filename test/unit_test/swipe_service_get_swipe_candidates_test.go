@@ -6,16 +6,21 @@ import (
 	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
 	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetSwipeCandidates(t *testing.T) {
 	mockRepo := new(userMock.MockUserRepository)
-	service := services.NewSwipeService(mockRepo)
+	service := services.NewSwipeService(mockRepo, repositories.NewMatchRepository())
 
 	today := time.Now().Truncate(24 * time.Hour)
+	bornYearsAgo := func(years int) models.Date {
+		return models.NewDate(today.Year()-years, time.January, 1)
+	}
 
 	testCases := []struct {
 		name          string
@@ -29,13 +34,16 @@ func TestGetSwipeCandidates(t *testing.T) {
 			setupMocks: func() {
 				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "male"}, nil)
 
-				mockRepo.On("GetAllUsers").Return([]*models.User{
+				mockRepo.On("GetUsersAfter", 0, 100).Return([]*models.User{
 					{ID: 2, Gender: "female", IsInactive: false},
 					{ID: 3, Gender: "female", IsInactive: false},
 					{ID: 4, Gender: "male", IsInactive: false},
 				})
 
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
 				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(map[int]models.SwipeActivity{}, nil)
 			},
 			userID: 1,
 			expectedUsers: []models.User{
@@ -44,6 +52,56 @@ func TestGetSwipeCandidates(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "Success - Both Sides' Preferences Honoured",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "female",
+					Preferences: models.Preferences{InterestedIn: []string{"male", "female"}}}, nil)
+
+				mockRepo.On("GetUsersAfter", 0, 100).Return([]*models.User{
+					{ID: 2, Gender: "male"},
+					{ID: 3, Gender: "female"},
+					{ID: 4, Gender: "female", Preferences: models.Preferences{InterestedIn: []string{"female"}}},
+					{ID: 5, Gender: "male", Preferences: models.Preferences{InterestedIn: []string{"male"}}},
+				})
+
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(map[int]models.SwipeActivity{}, nil)
+			},
+			userID: 1,
+			expectedUsers: []models.User{
+				{ID: 2, Gender: "male"},
+				{ID: 4, Gender: "female", Preferences: models.Preferences{InterestedIn: []string{"female"}}},
+			},
+			expectedError: "",
+		},
+		{
+			name: "Success - Age Ranges Honoured Both Ways",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "male", Birthdate: bornYearsAgo(30),
+					Preferences: models.Preferences{InterestedIn: []string{"female"}, MinAge: 25, MaxAge: 35}}, nil)
+
+				mockRepo.On("GetUsersAfter", 0, 100).Return([]*models.User{
+					{ID: 2, Gender: "female", Birthdate: bornYearsAgo(28)},
+					{ID: 3, Gender: "female", Birthdate: bornYearsAgo(22)},
+					{ID: 4, Gender: "female", Birthdate: bornYearsAgo(40)},
+					{ID: 5, Gender: "female", Birthdate: bornYearsAgo(27), Preferences: models.Preferences{InterestedIn: []string{"male"}, MaxAge: 29}},
+					{ID: 6, Gender: "female"},
+				})
+
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(map[int]models.SwipeActivity{}, nil)
+			},
+			userID: 1,
+			expectedUsers: []models.User{
+				{ID: 2, Gender: "female", Birthdate: bornYearsAgo(28)},
+			},
+			expectedError: "",
+		},
 		{
 			name: "Error - User Not Found",
 			setupMocks: func() {
@@ -53,12 +111,26 @@ func TestGetSwipeCandidates(t *testing.T) {
 			expectedUsers: nil,
 			expectedError: "user not found",
 		},
+		{
+			name: "Error - Activity Lookup Fails",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "male"}, nil)
+				mockRepo.On("GetUsersAfter", 0, 100).Return([]*models.User{{ID: 2, Gender: "female"}})
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(nil, errors.New("database is locked"))
+			},
+			userID:        1,
+			expectedUsers: nil,
+			expectedError: "database is locked",
+		},
 		{
 			name: "Success - Filter Swiped and Inactive Users",
 			setupMocks: func() {
 				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "female"}, nil)
 
-				mockRepo.On("GetAllUsers").Return([]*models.User{
+				mockRepo.On("GetUsersAfter", 0, 100).Return([]*models.User{
 					{ID: 2, Gender: "male", IsInactive: false},
 					{ID: 3, Gender: "male", IsInactive: true},
 					{ID: 4, Gender: "male", IsInactive: false},
@@ -66,9 +138,12 @@ func TestGetSwipeCandidates(t *testing.T) {
 					{ID: 6, Gender: "male", IsInactive: false},
 				})
 
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
 				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
 					{UserID: 1, TargetUserID: 4, CreatedAt: today.Add(1 * time.Hour)},
 				})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(map[int]models.SwipeActivity{}, nil)
 			},
 			userID: 1,
 			expectedUsers: []models.User{
@@ -84,11 +159,16 @@ func TestGetSwipeCandidates(t *testing.T) {
 			mockRepo.ExpectedCalls = nil
 			tc.setupMocks()
 
-			candidates, err := service.GetSwipeCandidates(tc.userID)
+			page, err := service.GetSwipeCandidates(tc.userID, "", 0)
 
 			if tc.expectedError == "" {
 				assert.Nil(t, err)
-				assert.Equal(t, tc.expectedUsers, candidates)
+				assert.Empty(t, page.NextCursor)
+				var users []models.User
+				for _, candidate := range page.Candidates {
+					users = append(users, candidate.User)
+				}
+				assert.Equal(t, tc.expectedUsers, users)
 			} else {
 				assert.NotNil(t, err)
 				assert.Equal(t, tc.expectedError, err.Error())
@@ -98,3 +178,80 @@ func TestGetSwipeCandidates(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSwipeCandidatesByDistance(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	service := services.NewSwipeService(mockRepo, repositories.NewMatchRepository())
+
+	// Roughly 1.1 km per 0.01 degree of latitude
+	jakarta := models.Location{Latitude: -6.2, Longitude: 106.8}
+	north := func(degrees float64) *models.Location {
+		return &models.Location{Latitude: jakarta.Latitude + degrees, Longitude: jakarta.Longitude}
+	}
+
+	testCases := []struct {
+		name        string
+		setupMocks  func()
+		expectedIDs []int
+		expectedKm  []float64
+	}{
+		{
+			name: "Success - Nearby Users Sorted By Distance",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "male", Location: &jakarta,
+					Preferences: models.Preferences{InterestedIn: []string{"female"}, MaxDistanceKm: 10}}, nil)
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(map[int]models.SwipeActivity{}, nil)
+				mockRepo.On("GetUsersNear", jakarta, 10.0).Return([]*models.User{
+					{ID: 2, Gender: "female", Location: north(0.05)},
+					{ID: 3, Gender: "female", Location: north(0.01)},
+					{ID: 4, Gender: "female", Location: north(0.03), Preferences: models.Preferences{InterestedIn: []string{"male"}, MaxDistanceKm: 2}},
+				})
+			},
+			expectedIDs: []int{3, 2},
+			expectedKm:  []float64{1.1, 5.6},
+		},
+		{
+			name: "Success - Without Maximum Distance Ordered By ID",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, Gender: "male", Location: &jakarta}, nil)
+				mockRepo.On("GetSwipesReceived", 1).Return([]models.Swipe{})
+				mockRepo.On("GetBoostedUsers", mock.Anything).Return([]*models.User{})
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+				mockRepo.On("GetSwipeActivity", mock.Anything, mock.Anything).Return(map[int]models.SwipeActivity{}, nil)
+				mockRepo.On("GetUsersAfter", 0, 100).Return([]*models.User{
+					{ID: 2, Gender: "female"},
+					{ID: 3, Gender: "female", Location: north(0.2)},
+					{ID: 4, Gender: "female", Location: north(0.1)},
+				})
+			},
+			expectedIDs: []int{2, 3, 4},
+			expectedKm:  []float64{-1, 22.2, 11.1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			page, err := service.GetSwipeCandidates(1, "", 0)
+
+			assert.Nil(t, err)
+			var ids []int
+			for i, candidate := range page.Candidates {
+				ids = append(ids, candidate.User.ID)
+				if tc.expectedKm[i] < 0 {
+					assert.Nil(t, candidate.DistanceKm)
+				} else {
+					assert.InDelta(t, tc.expectedKm[i], *candidate.DistanceKm, 0.1)
+				}
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -5,107 +5,174 @@ import (
 	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
-	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
 	"github.com/GradiyantoS/go-dealls-test-app/utils"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRecordSwipe(t *testing.T) {
-	mockRepo := new(userMock.MockUserRepository)
-	service := services.NewSwipeService(mockRepo)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	premium := &models.User{
+		ID:              1,
+		PremiumExpiry:   utils.TimePtr(time.Now().UTC().Add(24 * time.Hour)),
+		PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true},
+	}
 
-	today := time.Now().Truncate(24 * time.Hour)
+	// tenSwipes uses up the daily swipe limit of user 1
+	tenSwipes := func() []models.Swipe {
+		var swipes []models.Swipe
+		for target := 2; target <= 11; target++ {
+			swipes = append(swipes, models.Swipe{UserID: 1, TargetUserID: target, Action: "pass", CreatedAt: today.Add(time.Duration(target) * time.Minute)})
+		}
+		return swipes
+	}
 
 	testCases := []struct {
 		name          string
-		setupMocks    func()
+		user          *models.User
+		swipes        []models.Swipe
+		seed          func(t *testing.T, matchRepo repositories.MatchRepository)
 		swipe         *models.Swipe
+		expectedMatch bool
 		expectedError string
 	}{
 		{
-			name: "Success - Regular Swipe",
-			setupMocks: func() {
-				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
-				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, PremiumExpiry: nil}, nil)
-				mockRepo.On("SaveSwipe", mock.AnythingOfType("*models.Swipe")).Return(nil)
-			},
-			swipe: &models.Swipe{UserID: 1, TargetUserID: 2},
+			name:  "Success - Regular Swipe",
+			swipe: &models.Swipe{UserID: 1, TargetUserID: 2, Action: "pass"},
 		},
 		{
-			name: "Error - Already Swiped on Target User",
-			setupMocks: func() {
-				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
-					{UserID: 1, TargetUserID: 2, CreatedAt: today.Add(1 * time.Second)},
-				})
-			},
-			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2},
+			name:          "Error - Already Swiped on Target User",
+			swipes:        []models.Swipe{{UserID: 1, TargetUserID: 2, Action: "pass", CreatedAt: today.Add(1 * time.Second)}},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "pass"},
 			expectedError: "you have already swiped on this profile today",
 		},
 		{
-			name: "Error - Swipe Limit Reached",
-			setupMocks: func() {
-				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
-					{UserID: 1, TargetUserID: 2, CreatedAt: today.Add(1 * time.Hour)},
-					{UserID: 1, TargetUserID: 3, CreatedAt: today.Add(2 * time.Hour)},
-					{UserID: 1, TargetUserID: 4, CreatedAt: today.Add(3 * time.Hour)},
-					{UserID: 1, TargetUserID: 5, CreatedAt: today.Add(4 * time.Hour)},
-					{UserID: 1, TargetUserID: 6, CreatedAt: today.Add(5 * time.Hour)},
-					{UserID: 1, TargetUserID: 7, CreatedAt: today.Add(6 * time.Hour)},
-					{UserID: 1, TargetUserID: 8, CreatedAt: today.Add(7 * time.Hour)},
-					{UserID: 1, TargetUserID: 9, CreatedAt: today.Add(8 * time.Hour)},
-					{UserID: 1, TargetUserID: 10, CreatedAt: today.Add(9 * time.Hour)},
-					{UserID: 1, TargetUserID: 11, CreatedAt: today.Add(10 * time.Hour)},
-				})
-				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, PremiumExpiry: nil}, nil)
-			},
-			swipe:         &models.Swipe{UserID: 1, TargetUserID: 12},
+			name:          "Error - Swipe Limit Reached",
+			swipes:        tenSwipes(),
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 12, Action: "pass"},
 			expectedError: "daily swipe limit reached",
 		},
 		{
-			name: "Success - Unlimited Swipes Premium User",
-			setupMocks: func() {
-				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
-					{UserID: 1, TargetUserID: 2, CreatedAt: today.Add(1 * time.Hour)},
-					{UserID: 1, TargetUserID: 3, CreatedAt: today.Add(2 * time.Hour)},
-					{UserID: 1, TargetUserID: 4, CreatedAt: today.Add(3 * time.Hour)},
-					{UserID: 1, TargetUserID: 5, CreatedAt: today.Add(4 * time.Hour)},
-					{UserID: 1, TargetUserID: 6, CreatedAt: today.Add(5 * time.Hour)},
-					{UserID: 1, TargetUserID: 7, CreatedAt: today.Add(6 * time.Hour)},
-					{UserID: 1, TargetUserID: 8, CreatedAt: today.Add(7 * time.Hour)},
-					{UserID: 1, TargetUserID: 9, CreatedAt: today.Add(8 * time.Hour)},
-					{UserID: 1, TargetUserID: 10, CreatedAt: today.Add(9 * time.Hour)},
-					{UserID: 1, TargetUserID: 11, CreatedAt: today.Add(10 * time.Hour)},
-				})
-				mockRepo.On("GetUserByID", 1).Return(&models.User{
-					ID:              1,
-					PremiumExpiry:   utils.TimePtr(time.Now().UTC().Add(24 * time.Hour)),
-					PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true},
-				}, nil)
-				mockRepo.On("SaveSwipe", mock.AnythingOfType("*models.Swipe")).Return(nil)
+			name:   "Success - Unlimited Swipes Premium User",
+			user:   premium,
+			swipes: tenSwipes(),
+			swipe:  &models.Swipe{UserID: 1, TargetUserID: 12, Action: "pass"},
+		},
+		{
+			name:          "Success - Like Without Like Back",
+			swipes:        []models.Swipe{{UserID: 2, TargetUserID: 1, Action: "pass", CreatedAt: today.Add(1 * time.Hour)}},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "like"},
+			expectedMatch: false,
+		},
+		{
+			name:          "Success - Mutual Like Creates Match",
+			swipes:        []models.Swipe{{UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: today.Add(-48 * time.Hour)}},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "like"},
+			expectedMatch: true,
+		},
+		{
+			name:   "Success - Like From Before Unmatch Does Not Match Again",
+			swipes: []models.Swipe{{UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: today.Add(-48 * time.Hour)}},
+			seed: func(t *testing.T, matchRepo repositories.MatchRepository) {
+				match := &models.Match{ID: matchRepo.GenerateMatchID(), UserID: 2, MatchedID: 1, CreatedAt: today.Add(-48 * time.Hour)}
+				require.NoError(t, matchRepo.SaveMatch(match))
+				require.NoError(t, matchRepo.DeleteMatch(match.ID, today.Add(-24*time.Hour)))
 			},
-			swipe:         &models.Swipe{UserID: 1, TargetUserID: 12},
-			expectedError: "",
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "like"},
+			expectedMatch: false,
+		},
+		{
+			name: "Success - Like After Unmatch Matches Again",
+			swipes: []models.Swipe{
+				{UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: today.Add(-48 * time.Hour)},
+				{UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: today.Add(-12 * time.Hour)},
+			},
+			seed: func(t *testing.T, matchRepo repositories.MatchRepository) {
+				match := &models.Match{ID: matchRepo.GenerateMatchID(), UserID: 2, MatchedID: 1, CreatedAt: today.Add(-48 * time.Hour)}
+				require.NoError(t, matchRepo.SaveMatch(match))
+				require.NoError(t, matchRepo.DeleteMatch(match.ID, today.Add(-24*time.Hour)))
+			},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "like"},
+			expectedMatch: true,
+		},
+		{
+			name:   "Success - Already Matched Does Not Create Another Match",
+			swipes: []models.Swipe{{UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: today.Add(-48 * time.Hour)}},
+			seed: func(t *testing.T, matchRepo repositories.MatchRepository) {
+				require.NoError(t, matchRepo.SaveMatch(&models.Match{ID: matchRepo.GenerateMatchID(), UserID: 2, MatchedID: 1, CreatedAt: today.Add(-48 * time.Hour)}))
+			},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "like"},
+			expectedMatch: false,
+		},
+		{
+			name:          "Error - Invalid Action",
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "maybe"},
+			expectedError: "action must be like, pass or super_like",
+		},
+		{
+			name:          "Error - Super Like Limit Reached",
+			swipes:        []models.Swipe{{UserID: 1, TargetUserID: 2, Action: "super_like", CreatedAt: today.Add(1 * time.Hour)}},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 3, Action: "super_like"},
+			expectedError: "daily super like limit reached",
+		},
+		{
+			// Ten regular swipes and a super like, a premium user still has super likes left
+			name:   "Success - Super Likes Have Their Own Quota",
+			user:   &models.User{ID: 1, PremiumExpiry: utils.TimePtr(time.Now().Add(24 * time.Hour))},
+			swipes: append(tenSwipes(), models.Swipe{UserID: 1, TargetUserID: 12, Action: "super_like", CreatedAt: today.Add(time.Minute)}),
+			swipe:  &models.Swipe{UserID: 1, TargetUserID: 13, Action: "super_like"},
+		},
+		{
+			name:          "Success - Super Like On A Like Creates Match",
+			swipes:        []models.Swipe{{UserID: 2, TargetUserID: 1, Action: "like", CreatedAt: today.Add(-48 * time.Hour)}},
+			swipe:         &models.Swipe{UserID: 1, TargetUserID: 2, Action: "super_like"},
+			expectedMatch: true,
 		},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Reset mock expectations for each test case
-			mockRepo.ExpectedCalls = nil
-			tc.setupMocks()
+	for name, newRepo := range userRepositoryFactories(t) {
+		for _, tc := range testCases {
+			t.Run(name+" - "+tc.name, func(t *testing.T) {
+				repo := newRepo()
+				matchRepo := repositories.NewMatchRepositoryFor(repo)
+				service := services.NewSwipeService(repo, matchRepo)
+
+				user := &models.User{ID: 1}
+				if tc.user != nil {
+					copied := *tc.user
+					user = &copied
+				}
+				seedUsers(t, repo, user)
+				seedSwipes(t, repo, tc.swipes...)
+				if tc.seed != nil {
+					tc.seed(t, matchRepo)
+				}
+				matchesBefore := len(matchRepo.GetMatchesForUser(tc.swipe.UserID))
 
-			err := service.RecordSwipe(tc.swipe)
+				swipe := *tc.swipe
+				match, err := service.RecordSwipe(&swipe)
 
-			if tc.expectedError == "" {
-				assert.Nil(t, err)
-			} else {
-				assert.NotNil(t, err)
-				assert.Equal(t, tc.expectedError, err.Error())
-			}
+				if tc.expectedError == "" {
+					assert.Nil(t, err)
+					assert.Equal(t, tc.expectedMatch, match != nil)
+					assert.NotZero(t, swipe.ID)
+				} else {
+					assert.NotNil(t, err)
+					assert.Equal(t, tc.expectedError, err.Error())
+				}
 
-			mockRepo.AssertExpectations(t)
-		})
+				// A match is stored exactly when one is returned
+				matchesAfter := matchRepo.GetMatchesForUser(tc.swipe.UserID)
+				if match != nil {
+					require.Len(t, matchesAfter, matchesBefore+1)
+					_, err := matchRepo.GetMatchBetween(swipe.UserID, swipe.TargetUserID)
+					assert.NoError(t, err)
+				} else {
+					assert.Len(t, matchesAfter, matchesBefore)
+				}
+			})
+		}
 	}
 }
@@ -0,0 +1,125 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewindLastSwipe(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockMatchRepo := new(userMock.MockMatchRepository)
+	service := services.NewSwipeService(mockRepo, mockMatchRepo)
+
+	premium := &models.User{
+		ID:              1,
+		PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+		PremiumFeatures: models.PremiumFeatures{Rewind: true},
+	}
+	recent := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		expectedSwipe int
+		expectedError string
+	}{
+		{
+			name: "Success - Most Recent Swipe Is Removed",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
+					{ID: 3, UserID: 1, TargetUserID: 2, Action: "like", CreatedAt: recent.Add(-time.Second)},
+					{ID: 7, UserID: 1, TargetUserID: 4, Action: "pass", CreatedAt: recent},
+				})
+				mockRepo.On("DeleteSwipe", 7).Return(nil)
+			},
+			expectedSwipe: 7,
+		},
+		{
+			name: "Success - Like Without Match",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{{ID: 3, UserID: 1, TargetUserID: 2, Action: "super_like", CreatedAt: recent}})
+				mockMatchRepo.On("GetMatchBetween", 1, 2).Return(nil, errors.New("match not found"))
+				mockRepo.On("DeleteSwipe", 3).Return(nil)
+			},
+			expectedSwipe: 3,
+		},
+		{
+			name: "Error - Without Rewind Feature",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+					PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true},
+				}, nil)
+			},
+			expectedError: "rewind requires an active premium plan with rewind",
+		},
+		{
+			name: "Error - Premium Expired",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   utils.TimePtr(time.Now().Add(-time.Hour)),
+					PremiumFeatures: models.PremiumFeatures{Rewind: true},
+				}, nil)
+			},
+			expectedError: "rewind requires an active premium plan with rewind",
+		},
+		{
+			name: "Error - No Swipes",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{})
+			},
+			expectedError: "there is no swipe from the last 5 minutes to rewind",
+		},
+		{
+			name: "Error - Last Swipe Outside The Window",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{
+					{ID: 3, UserID: 1, TargetUserID: 2, Action: "pass", CreatedAt: time.Now().Add(-services.RewindWindow - time.Second)},
+				})
+			},
+			expectedError: "there is no swipe from the last 5 minutes to rewind",
+		},
+		{
+			name: "Error - Like Made A Match",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium, nil)
+				mockRepo.On("GetSwipesForUser", 1).Return([]models.Swipe{{ID: 3, UserID: 1, TargetUserID: 2, Action: "like", CreatedAt: recent}})
+				mockMatchRepo.On("GetMatchBetween", 1, 2).Return(&models.Match{ID: 1, UserID: 2, MatchedID: 1}, nil)
+			},
+			expectedError: "a swipe that made a match can't be rewound, unmatch instead",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockMatchRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			swipe, err := service.RewindLastSwipe(1)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSwipe, swipe.ID)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockMatchRepo.AssertExpectations(t)
+		})
+	}
+}
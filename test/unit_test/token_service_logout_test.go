@@ -0,0 +1,83 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLogout(t *testing.T) {
+	mockTokenRepo := new(userMock.MockTokenRepository)
+	service := services.NewTokenService(mockTokenRepo)
+
+	expiry := time.Now().Add(10 * time.Minute)
+	claims := &utils.TokenClaims{UserID: 1, ID: "jti-1", ExpiresAt: expiry}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		refreshToken  string
+		expectedError string
+	}{
+		{
+			name: "Success - Revoke Access Token Only",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeAccessToken", "jti-1", expiry).Return(nil)
+			},
+		},
+		{
+			name: "Success - Revoke Access And Refresh Token",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeAccessToken", "jti-1", expiry).Return(nil)
+				mockTokenRepo.On("GetRefreshToken", hashOf("refresh")).Return(&models.RefreshToken{UserID: 1, ExpiresAt: expiry}, nil)
+				mockTokenRepo.On("UpdateRefreshToken", mock.MatchedBy(func(token *models.RefreshToken) bool {
+					return token.RevokedAt != nil
+				})).Return(nil)
+			},
+			refreshToken: "refresh",
+		},
+		{
+			name: "Error - Refresh Token Of Another User",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeAccessToken", "jti-1", expiry).Return(nil)
+				mockTokenRepo.On("GetRefreshToken", hashOf("refresh")).Return(&models.RefreshToken{UserID: 2, ExpiresAt: expiry}, nil)
+			},
+			refreshToken:  "refresh",
+			expectedError: "invalid refresh token",
+		},
+		{
+			name: "Error - Unknown Refresh Token",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeAccessToken", "jti-1", expiry).Return(nil)
+				mockTokenRepo.On("GetRefreshToken", hashOf("refresh")).Return(nil, errors.New("refresh token not found"))
+			},
+			refreshToken:  "refresh",
+			expectedError: "invalid refresh token",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTokenRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			err := service.Logout(1, claims, tc.refreshToken)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package unit_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func hashOf(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRefreshTokens(t *testing.T) {
+	mockTokenRepo := new(userMock.MockTokenRepository)
+	service := services.NewTokenService(mockTokenRepo)
+
+	originalGenerateJWT := utils.GenerateJWT
+	utils.GenerateJWT = func(userID int) (string, error) {
+		return "mocked-jwt-token", nil
+	}
+	defer func() { utils.GenerateJWT = originalGenerateJWT }()
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		refreshToken  string
+		expectedError string
+	}{
+		{
+			name: "Success - Rotates Refresh Token",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeRefreshToken", hashOf("valid-token"), mock.AnythingOfType("time.Time")).Return(&models.RefreshToken{
+					TokenHash: hashOf("valid-token"),
+					UserID:    1,
+					ExpiresAt: time.Now().Add(time.Hour),
+					RevokedAt: utils.TimePtr(time.Now()),
+				}, nil)
+				mockTokenRepo.On("SaveRefreshToken", mock.MatchedBy(func(token *models.RefreshToken) bool {
+					return token.UserID == 1 && token.TokenHash != hashOf("valid-token")
+				})).Return(nil)
+			},
+			refreshToken: "valid-token",
+		},
+		{
+			name: "Error - Unknown Refresh Token",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeRefreshToken", hashOf("unknown-token"), mock.AnythingOfType("time.Time")).Return(nil, errors.New("refresh token not found"))
+			},
+			refreshToken:  "unknown-token",
+			expectedError: "invalid refresh token",
+		},
+		{
+			name: "Error - Revoked Refresh Token",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeRefreshToken", hashOf("revoked-token"), mock.AnythingOfType("time.Time")).Return(nil, errors.New("refresh token already revoked"))
+			},
+			refreshToken:  "revoked-token",
+			expectedError: "invalid refresh token",
+		},
+		{
+			name: "Error - Expired Refresh Token",
+			setupMocks: func() {
+				mockTokenRepo.On("RevokeRefreshToken", hashOf("expired-token"), mock.AnythingOfType("time.Time")).Return(nil, errors.New("refresh token expired"))
+			},
+			refreshToken:  "expired-token",
+			expectedError: "invalid refresh token",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTokenRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			tokens, err := service.RefreshTokens(tc.refreshToken)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, "mocked-jwt-token", tokens.AccessToken)
+				assert.NotEqual(t, tc.refreshToken, tokens.RefreshToken)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
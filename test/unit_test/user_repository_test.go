@@ -0,0 +1,349 @@
+package unit_test
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userRepositoryFactories lists every UserRepository implementation the tests should run against
+func userRepositoryFactories(t *testing.T) map[string]func() repositories.UserRepository {
+	return map[string]func() repositories.UserRepository{
+		"InMemory": func() repositories.UserRepository {
+			return repositories.NewUserRepository()
+		},
+		"SQLite": func() repositories.UserRepository {
+			return newSQLiteUserRepository(t, filepath.Join(t.TempDir(), "test.db"))
+		},
+	}
+}
+
+// newSQLiteUserRepository opens a SQLite repository that is closed when the test ends
+func newSQLiteUserRepository(t *testing.T, path string) repositories.UserRepository {
+	repo, err := repositories.NewSQLiteUserRepository(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.(io.Closer).Close() })
+	return repo
+}
+
+// seedUsers saves the users, empty emails and phones are derived from the ID since both must be unique
+func seedUsers(t *testing.T, repo repositories.UserRepository, users ...*models.User) {
+	for _, user := range users {
+		if user.Email == "" {
+			user.Email = fmt.Sprintf("user%d@example.com", user.ID)
+		}
+		if user.Phone == "" {
+			user.Phone = fmt.Sprint(1000000 + user.ID)
+		}
+		require.NoError(t, repo.SaveUser(user))
+	}
+}
+
+// seedSwipes saves the swipes as they are, unlike RecordSwipe which stamps them with the current time
+func seedSwipes(t *testing.T, repo repositories.UserRepository, swipes ...models.Swipe) {
+	for i := range swipes {
+		require.NoError(t, repo.SaveSwipe(&swipes[i]))
+	}
+}
+
+func TestUserRepository(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			user := &models.User{
+				ID:        repo.GenerateUserID(),
+				Email:     "test1@example.com",
+				Password:  "hashed",
+				Phone:     "1234567890",
+				Name:      "User 1",
+				Gender:    "male",
+				Birthdate: models.NewDate(1995, time.April, 23),
+				CreatedAt: time.Now().UTC(),
+				UpdatedAt: time.Now().UTC(),
+			}
+			require.NoError(t, repo.SaveUser(user))
+
+			t.Run("Error - Duplicate User ID", func(t *testing.T) {
+				err := repo.SaveUser(&models.User{ID: user.ID, Email: "other@example.com", Phone: "000"})
+				assert.EqualError(t, err, "user ID already exists")
+			})
+
+			t.Run("Success - Lookups", func(t *testing.T) {
+				byID, err := repo.GetUserByID(user.ID)
+				assert.NoError(t, err)
+				assert.Equal(t, "User 1", byID.Name)
+				assert.Equal(t, user.Birthdate, byID.Birthdate)
+
+				byEmail, err := repo.GetUserByEmail("test1@example.com")
+				assert.NoError(t, err)
+				assert.Equal(t, user.ID, byEmail.ID)
+
+				byPhone, err := repo.GetUserByPhone("1234567890")
+				assert.NoError(t, err)
+				assert.Equal(t, user.ID, byPhone.ID)
+
+				assert.Len(t, repo.GetAllUsers(), 1)
+			})
+
+			t.Run("Error - User Not Found", func(t *testing.T) {
+				_, err := repo.GetUserByID(999)
+				assert.EqualError(t, err, "user not found")
+				_, err = repo.GetUserByEmail("missing@example.com")
+				assert.EqualError(t, err, "user not found")
+				err = repo.UpdateUser(&models.User{ID: 999})
+				assert.EqualError(t, err, "user not found")
+			})
+
+			t.Run("Success - Update Premium", func(t *testing.T) {
+				expiry := time.Now().UTC().Add(24 * time.Hour)
+				user.PremiumExpiry = utils.TimePtr(expiry)
+				user.PremiumFeatures.UnlimitedSwipes = true
+				user.PremiumFeatureExpiry.UnlimitedSwipes = utils.TimePtr(expiry)
+				require.NoError(t, repo.UpdateUser(user))
+
+				updated, err := repo.GetUserByID(user.ID)
+				assert.NoError(t, err)
+				assert.True(t, updated.PremiumFeatures.UnlimitedSwipes)
+				assert.NotNil(t, updated.PremiumExpiry)
+				assert.WithinDuration(t, expiry, *updated.PremiumExpiry, time.Second)
+				require.NotNil(t, updated.PremiumFeatureExpiry.UnlimitedSwipes)
+				assert.WithinDuration(t, expiry, *updated.PremiumFeatureExpiry.UnlimitedSwipes, time.Second)
+				assert.Nil(t, updated.PremiumFeatureExpiry.ProfileBoost)
+			})
+
+			t.Run("Error - Phone Taken By Another User", func(t *testing.T) {
+				other := &models.User{ID: repo.GenerateUserID(), Email: "test2@example.com", Phone: "2222222222"}
+				require.NoError(t, repo.SaveUser(other))
+				other.Phone = user.Phone
+				assert.ErrorIs(t, repo.UpdateUser(other), repositories.ErrPhoneAlreadyExists)
+				assert.ErrorIs(t, repo.SaveUser(&models.User{ID: repo.GenerateUserID(), Email: "test3@example.com", Phone: user.Phone}), repositories.ErrPhoneAlreadyExists)
+			})
+
+			t.Run("Success - Update Profile", func(t *testing.T) {
+				user.Bio = "Coffee first"
+				user.Occupation = "Engineer"
+				user.Interests = []string{"hiking", "jazz"}
+				user.Preferences = models.Preferences{InterestedIn: []string{"female"}, MinAge: 21, MaxAge: 30, MaxDistanceKm: 15}
+				require.NoError(t, repo.UpdateUser(user))
+
+				updated, err := repo.GetUserByID(user.ID)
+				assert.NoError(t, err)
+				assert.Equal(t, "Coffee first", updated.Bio)
+				assert.Equal(t, "Engineer", updated.Occupation)
+				assert.Equal(t, []string{"hiking", "jazz"}, updated.Interests)
+				assert.Equal(t, user.Preferences, updated.Preferences)
+			})
+
+			t.Run("Success - Swipes", func(t *testing.T) {
+				require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: user.ID, TargetUserID: 2, Action: "like", CreatedAt: time.Now().UTC()}))
+				require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 2, TargetUserID: user.ID, Action: "pass", CreatedAt: time.Now().UTC()}))
+
+				swipes := repo.GetSwipesForUser(user.ID)
+				assert.Len(t, swipes, 1)
+				assert.Equal(t, 2, swipes[0].TargetUserID)
+				assert.Equal(t, "like", swipes[0].Action)
+
+				received := repo.GetSwipesReceived(user.ID)
+				assert.Len(t, received, 1)
+				assert.Equal(t, 2, received[0].UserID)
+				assert.Equal(t, "pass", received[0].Action)
+				assert.NotEqual(t, swipes[0].ID, received[0].ID)
+
+				require.NoError(t, repo.DeleteSwipe(swipes[0].ID))
+				assert.Empty(t, repo.GetSwipesForUser(user.ID))
+				assert.Len(t, repo.GetSwipesReceived(user.ID), 1)
+				assert.EqualError(t, repo.DeleteSwipe(swipes[0].ID), "swipe not found")
+			})
+
+			t.Run("Success - Generated IDs Stay Unique", func(t *testing.T) {
+				assert.NotEqual(t, user.ID, repo.GenerateUserID())
+			})
+		})
+	}
+}
+
+func TestUserRepositoryGetUsersAfter(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			// Saved out of order, pages still come back ordered by ID
+			for _, id := range []int{5, 2, 9, 1, 7} {
+				require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id)}))
+			}
+
+			ids := func(users []*models.User) []int {
+				var result []int
+				for _, user := range users {
+					result = append(result, user.ID)
+				}
+				return result
+			}
+
+			assert.Equal(t, []int{1, 2, 5}, ids(repo.GetUsersAfter(0, 3)))
+			assert.Equal(t, []int{7, 9}, ids(repo.GetUsersAfter(5, 3)))
+			assert.Equal(t, []int{9}, ids(repo.GetUsersAfter(8, 3)))
+			assert.Empty(t, repo.GetUsersAfter(9, 3))
+		})
+	}
+}
+
+func TestUserRepositoryGetSwipeActivity(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			for id := 1; id <= 3; id++ {
+				require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id)}))
+			}
+			earlier := time.Now().UTC().Add(-time.Hour)
+			later := time.Now().UTC()
+			require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 1, TargetUserID: 2, Action: "like", CreatedAt: earlier}))
+			require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 1, TargetUserID: 3, Action: "pass", CreatedAt: later}))
+			require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 3, TargetUserID: 2, Action: "pass", CreatedAt: earlier}))
+			require.NoError(t, repo.SaveSwipe(&models.Swipe{UserID: 4, TargetUserID: 2, Action: "super_like", CreatedAt: earlier}))
+
+			activity, err := repo.GetSwipeActivity([]int{1, 2}, time.Now())
+
+			require.NoError(t, err)
+			assert.Len(t, activity, 2)
+			assert.WithinDuration(t, later, activity[1].LastSwipeAt, time.Millisecond)
+			assert.Zero(t, activity[1].SwipesReceived)
+			assert.Equal(t, 2, activity[2].LikesReceived)
+			assert.Equal(t, 3, activity[2].SwipesReceived)
+			assert.True(t, activity[2].LastSwipeAt.IsZero())
+			activity, err = repo.GetSwipeActivity(nil, time.Now())
+			require.NoError(t, err)
+			assert.Empty(t, activity)
+
+			// Swipes after asOf are left out
+			activity, err = repo.GetSwipeActivity([]int{1, 3}, earlier)
+			require.NoError(t, err)
+			assert.Len(t, activity, 2)
+			assert.WithinDuration(t, earlier, activity[3].LastSwipeAt, time.Millisecond)
+			assert.Zero(t, activity[3].SwipesReceived)
+			assert.WithinDuration(t, earlier, activity[1].LastSwipeAt, time.Millisecond)
+
+			// More users than fit in one SQLite query
+			ids := make([]int, 2000)
+			for i := range ids {
+				ids[i] = i + 1
+			}
+			activity, err = repo.GetSwipeActivity(ids, time.Now())
+			require.NoError(t, err)
+			assert.Len(t, activity, 4)
+			assert.Equal(t, 3, activity[2].SwipesReceived)
+		})
+	}
+}
+
+func TestUserRepositoryGetUsersNear(t *testing.T) {
+	locations := map[string]models.Location{
+		"jakarta":   {Latitude: -6.2000, Longitude: 106.8166},
+		"depok":     {Latitude: -6.4025, Longitude: 106.7942},
+		"bandung":   {Latitude: -6.9175, Longitude: 107.6191},
+		"west_fiji": {Latitude: -17.7134, Longitude: 179.9},
+		"east_fiji": {Latitude: -17.7134, Longitude: -179.9},
+	}
+
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			ids := map[string]int{}
+			i := 0
+			for place, location := range locations {
+				location := location
+				i++
+				ids[place] = i
+				require.NoError(t, repo.SaveUser(&models.User{ID: i, Email: place + "@example.com", Phone: place, Location: &location}))
+			}
+			require.NoError(t, repo.SaveUser(&models.User{ID: 99, Email: "nowhere@example.com", Phone: "nowhere"}))
+
+			near := func(center models.Location, radiusKm float64) []int {
+				var result []int
+				for _, user := range repo.GetUsersNear(center, radiusKm) {
+					result = append(result, user.ID)
+				}
+				return result
+			}
+
+			t.Run("Success - Only Users Within The Radius", func(t *testing.T) {
+				assert.ElementsMatch(t, []int{ids["jakarta"], ids["depok"]}, near(locations["jakarta"], 50))
+				assert.ElementsMatch(t, []int{ids["jakarta"], ids["depok"], ids["bandung"]}, near(locations["jakarta"], 150))
+			})
+
+			t.Run("Success - Search Wraps Around The Antimeridian", func(t *testing.T) {
+				assert.ElementsMatch(t, []int{ids["west_fiji"], ids["east_fiji"]}, near(locations["west_fiji"], 50))
+			})
+
+			t.Run("Success - Moving A User Updates The Index", func(t *testing.T) {
+				user, err := repo.GetUserByID(ids["bandung"])
+				require.NoError(t, err)
+				user.Location = &models.Location{Latitude: -6.21, Longitude: 106.82}
+				require.NoError(t, repo.UpdateUser(user))
+				assert.ElementsMatch(t, []int{ids["jakarta"], ids["depok"], ids["bandung"]}, near(locations["jakarta"], 50))
+
+				user.Location = nil
+				require.NoError(t, repo.UpdateUser(user))
+				assert.ElementsMatch(t, []int{ids["jakarta"], ids["depok"]}, near(locations["jakarta"], 50))
+			})
+		})
+	}
+}
+
+func TestSQLiteUserRepositoryUniqueConstraints(t *testing.T) {
+	repo := newSQLiteUserRepository(t, filepath.Join(t.TempDir(), "test.db"))
+
+	require.NoError(t, repo.SaveUser(&models.User{ID: 1, Email: "a@example.com", Phone: "111"}))
+	assert.ErrorIs(t, repo.SaveUser(&models.User{ID: 2, Email: "a@example.com", Phone: "222"}), repositories.ErrEmailAlreadyExists)
+	assert.ErrorIs(t, repo.SaveUser(&models.User{ID: 3, Email: "b@example.com", Phone: "111"}), repositories.ErrPhoneAlreadyExists)
+}
+
+func TestSQLiteUserRepositoryPersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	repo := newSQLiteUserRepository(t, path)
+	require.NoError(t, repo.SaveUser(&models.User{ID: repo.GenerateUserID(), Email: "a@example.com", Phone: "111"}))
+
+	reopened := newSQLiteUserRepository(t, path)
+
+	user, err := reopened.GetUserByEmail("a@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, 2, reopened.GenerateUserID())
+}
+
+func TestUserRepositoryGetBoostedUsers(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			now := time.Now().UTC()
+			boosts := map[int]*time.Time{
+				1: utils.TimePtr(now.Add(30 * time.Minute)),
+				2: utils.TimePtr(now.Add(-time.Minute)),
+				// Expired less than a second ago, only the exact time tells it apart
+				3: utils.TimePtr(now.Add(-time.Millisecond)),
+				4: nil,
+				5: utils.TimePtr(now.Add(time.Millisecond)),
+			}
+			for id, boostedUntil := range boosts {
+				require.NoError(t, repo.SaveUser(&models.User{ID: id, Email: fmt.Sprintf("u%d@example.com", id), Phone: fmt.Sprint(id), BoostedUntil: boostedUntil}))
+			}
+
+			var ids []int
+			for _, user := range repo.GetBoostedUsers(now) {
+				ids = append(ids, user.ID)
+				assert.NotNil(t, user.BoostedUntil)
+			}
+			assert.Equal(t, []int{1, 5}, ids)
+			assert.Empty(t, repo.GetBoostedUsers(now.Add(time.Hour)))
+		})
+	}
+}
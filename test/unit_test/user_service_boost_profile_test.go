@@ -0,0 +1,119 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBoostProfile(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	service := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+
+	premium := func(boostedUntil *time.Time) *models.User {
+		return &models.User{
+			ID:              1,
+			PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+			PremiumFeatures: models.PremiumFeatures{ProfileBoost: true},
+			BoostedUntil:    boostedUntil,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium(nil), nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.IsBoosted(time.Now()) && !user.IsBoosted(time.Now().Add(services.BoostDuration))
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Success - Cooldown Over",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium(utils.TimePtr(time.Now().Add(-services.BoostCooldown))), nil)
+				mockRepo.On("UpdateUser", mock.AnythingOfType("*models.User")).Return(nil)
+			},
+		},
+		{
+			name: "Error - Free User",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+			},
+			expectedError: services.ErrBoostNotAvailable,
+		},
+		{
+			name: "Error - Plan Without Profile Boost",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+					PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true},
+				}, nil)
+			},
+			expectedError: services.ErrBoostNotAvailable,
+		},
+		{
+			name: "Error - Premium Expired",
+			setupMocks: func() {
+				user := premium(nil)
+				user.PremiumExpiry = utils.TimePtr(time.Now().Add(-time.Hour))
+				mockRepo.On("GetUserByID", 1).Return(user, nil)
+			},
+			expectedError: services.ErrBoostNotAvailable,
+		},
+		{
+			name: "Error - Boost Already Running",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium(utils.TimePtr(time.Now().Add(10*time.Minute))), nil)
+			},
+			expectedError: services.ErrBoostActive,
+		},
+		{
+			name: "Error - Cooling Down",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(premium(utils.TimePtr(time.Now().Add(-time.Hour))), nil)
+			},
+			expectedError: services.ErrBoostCoolingDown,
+		},
+		{
+			name: "Error - User Not Found",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
+			},
+			expectedError: errors.New("user not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			boostedUntil, err := service.BoostProfile(1)
+
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				assert.WithinDuration(t, time.Now().Add(services.BoostDuration), *boostedUntil, time.Second)
+			} else {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, boostedUntil)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
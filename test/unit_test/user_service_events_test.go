@@ -0,0 +1,54 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserServicePublishesPremiumChanges(t *testing.T) {
+	for name, newRepo := range userRepositoryFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			require.NoError(t, repo.SaveUser(&models.User{ID: 1, Email: "test1@example.com", Phone: "1"}))
+			publisher := new(userMock.MockEventPublisher)
+			service := services.NewUserServiceWith(repo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()), publisher)
+
+			publisher.On("Publish", mock.MatchedBy(func(event models.Event) bool {
+				features, ok := event.Data["premium_features"].(models.PremiumFeatures)
+				return event.Type == models.EventPremiumActivated && event.UserID == 1 && ok && features.UnlimitedSwipes
+			})).Once()
+			require.NoError(t, service.EnablePremiumFeature(1, 1, []string{"UnlimitedSwipes"}))
+			publisher.AssertExpectations(t)
+
+			publisher.ExpectedCalls = nil
+			publisher.On("Publish", mock.MatchedBy(func(event models.Event) bool {
+				return event.Type == models.EventPremiumExpired && event.UserID == 1 && len(event.Data["features"].([]string)) == 1
+			})).Once()
+			expired, err := service.ExpirePremiumFeatures(time.Now().Add(48 * time.Hour))
+			require.NoError(t, err)
+			require.Equal(t, 1, expired)
+			publisher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnablePremiumFeatureFailedWritePublishesNothing(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	publisher := new(userMock.MockEventPublisher)
+	service := services.NewUserServiceWith(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()), publisher)
+
+	mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+	mockRepo.On("UpdateUser", mock.AnythingOfType("*models.User")).Return(errors.New("disk full"))
+
+	assert.EqualError(t, service.EnablePremiumFeature(1, 30, []string{"UnlimitedSwipes"}), "disk full")
+	publisher.AssertNotCalled(t, "Publish", mock.Anything)
+}
@@ -0,0 +1,115 @@
+package unit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpirePremiumFeatures(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockEventRepo := new(userMock.MockPremiumEventRepository)
+	service := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), mockEventRepo, services.NewTokenService(repositories.NewTokenRepository()))
+
+	now := time.Now()
+	expiredUser := &models.User{
+		ID:              1,
+		PremiumExpiry:   utils.TimePtr(now.Add(-time.Hour)),
+		PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true},
+	}
+	activeUser := &models.User{
+		ID:              2,
+		PremiumExpiry:   utils.TimePtr(now.Add(time.Hour)),
+		PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true},
+	}
+	alreadyClearedUser := &models.User{
+		ID:            3,
+		PremiumExpiry: utils.TimePtr(now.Add(-time.Hour)),
+	}
+
+	testCases := []struct {
+		name            string
+		setupMocks      func()
+		expectedExpired int
+	}{
+		{
+			name: "Success - Expire Only Lapsed Users",
+			setupMocks: func() {
+				mockRepo.On("GetAllUsers").Return([]*models.User{expiredUser, activeUser, alreadyClearedUser})
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   expiredUser.PremiumExpiry,
+					PremiumFeatures: expiredUser.PremiumFeatures,
+				}, nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.ID == 1 && user.PremiumFeatures == models.PremiumFeatures{}
+				})).Return(nil)
+				mockEventRepo.On("SavePremiumEvent", mock.MatchedBy(func(event *models.PremiumEvent) bool {
+					return event.UserID == 1 && event.Type == models.PremiumEventExpired &&
+						assert.ObjectsAreEqual([]string{"UnlimitedSwipes", "ProfileBoost"}, event.Features)
+				})).Return(nil)
+			},
+			expectedExpired: 1,
+		},
+		{
+			name: "Success - Expire Only The Lapsed Features",
+			setupMocks: func() {
+				partlyLapsed := func() *models.User {
+					return &models.User{
+						ID:              4,
+						PremiumExpiry:   utils.TimePtr(now.Add(24 * time.Hour)),
+						PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true},
+						PremiumFeatureExpiry: models.PremiumFeatureExpiry{
+							UnlimitedSwipes: utils.TimePtr(now.Add(-time.Hour)),
+							ProfileBoost:    utils.TimePtr(now.Add(24 * time.Hour)),
+						},
+					}
+				}
+				mockRepo.On("GetAllUsers").Return([]*models.User{partlyLapsed(), activeUser})
+				mockRepo.On("GetUserByID", 4).Return(partlyLapsed(), nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.ID == 4 && user.PremiumFeatures == models.PremiumFeatures{ProfileBoost: true}
+				})).Return(nil)
+				mockEventRepo.On("SavePremiumEvent", mock.MatchedBy(func(event *models.PremiumEvent) bool {
+					return event.UserID == 4 && assert.ObjectsAreEqual([]string{"UnlimitedSwipes"}, event.Features)
+				})).Return(nil)
+			},
+			expectedExpired: 1,
+		},
+		{
+			name: "Success - Skip User Renewed Concurrently",
+			setupMocks: func() {
+				mockRepo.On("GetAllUsers").Return([]*models.User{expiredUser})
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   utils.TimePtr(now.Add(30 * 24 * time.Hour)),
+					PremiumFeatures: expiredUser.PremiumFeatures,
+				}, nil)
+			},
+			expectedExpired: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockEventRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			expired, err := service.ExpirePremiumFeatures(now)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedExpired, expired)
+
+			mockRepo.AssertExpectations(t)
+			mockEventRepo.AssertExpectations(t)
+		})
+	}
+}
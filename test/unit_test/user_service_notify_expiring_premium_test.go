@@ -0,0 +1,82 @@
+package unit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotifyExpiringPremium(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockEventRepo := new(userMock.MockPremiumEventRepository)
+	publisher := new(userMock.MockEventPublisher)
+	service := services.NewUserServiceWith(mockRepo, repositories.NewPlanRepository(nil), mockEventRepo, services.NewTokenService(repositories.NewTokenRepository()), publisher)
+
+	now := time.Now()
+	expiringSoon := utils.TimePtr(now.Add(time.Hour))
+	expiringUser := &models.User{ID: 1, PremiumExpiry: expiringSoon, PremiumFeatures: models.PremiumFeatures{Rewind: true}}
+	laterUser := &models.User{ID: 2, PremiumExpiry: utils.TimePtr(now.Add(services.PremiumExpiringNotice + time.Hour))}
+	expiredUser := &models.User{ID: 3, PremiumExpiry: utils.TimePtr(now.Add(-time.Hour))}
+	freeUser := &models.User{ID: 4}
+
+	testCases := []struct {
+		name           string
+		setupMocks     func()
+		expectedWarned int
+	}{
+		{
+			name: "Success - Warn Users Expiring Soon",
+			setupMocks: func() {
+				mockRepo.On("GetAllUsers").Return([]*models.User{expiringUser, laterUser, expiredUser, freeUser})
+				mockEventRepo.On("GetPremiumEventsForUser", 1).Return([]models.PremiumEvent{
+					// A warning about an earlier subscription doesn't count
+					{UserID: 1, Type: models.PremiumEventExpiring, ExpiresAt: utils.TimePtr(now.Add(-30 * 24 * time.Hour))},
+				})
+				mockEventRepo.On("SavePremiumEvent", mock.MatchedBy(func(event *models.PremiumEvent) bool {
+					return event.UserID == 1 && event.Type == models.PremiumEventExpiring && event.ExpiresAt.Equal(*expiringSoon) &&
+						len(event.Features) == 1 && event.Features[0] == "Rewind"
+				})).Return(nil)
+				publisher.On("Publish", mock.MatchedBy(func(event models.Event) bool {
+					return event.UserID == 1 && event.Type == models.EventPremiumExpiring
+				})).Once()
+			},
+			expectedWarned: 1,
+		},
+		{
+			name: "Success - Warned Once Per Expiry",
+			setupMocks: func() {
+				mockRepo.On("GetAllUsers").Return([]*models.User{expiringUser})
+				mockEventRepo.On("GetPremiumEventsForUser", 1).Return([]models.PremiumEvent{
+					{UserID: 1, Type: models.PremiumEventExpiring, ExpiresAt: expiringSoon},
+				})
+			},
+			expectedWarned: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockEventRepo.ExpectedCalls = nil
+			publisher.ExpectedCalls = nil
+			publisher.Calls = nil
+			tc.setupMocks()
+
+			warned, err := service.NotifyExpiringPremium(now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWarned, warned)
+			mockRepo.AssertExpectations(t)
+			mockEventRepo.AssertExpectations(t)
+			publisher.AssertExpectations(t)
+			publisher.AssertNumberOfCalls(t, "Publish", tc.expectedWarned)
+		})
+	}
+}
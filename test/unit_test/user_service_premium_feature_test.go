@@ -1,121 +1,137 @@
 package unit_test
 
 import (
-	"errors"
 	"testing"
+	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
-	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEnablePremiumFeature(t *testing.T) {
-	mockRepo := new(userMock.MockUserRepository)
-	service := services.NewUserService(mockRepo)
-
 	testCases := []struct {
 		name          string
-		setupMocks    func()
+		user          *models.User
 		userID        int
 		duration      int
 		features      []string
 		expectedError string
+		check         func(t *testing.T, saved *models.User)
 	}{
 		{
-			name: "Success - Enable Unlimited Swipes and Verified Badge",
-			setupMocks: func() {
-				mockRepo.On("GetUserByID", 1).Return(&models.User{
-					ID:            1,
-					IsInactive:    false,
-					PremiumExpiry: nil,
-					PremiumFeatures: models.PremiumFeatures{
-						UnlimitedSwipes: false,
-						IsVerified:      false,
-					},
-				}, nil)
-
-				mockRepo.On("UpdateUser", mock.AnythingOfType("*models.User")).Return(nil)
+			name:     "Success - Enable Unlimited Swipes and Profile Boost",
+			user:     &models.User{ID: 1},
+			userID:   1,
+			duration: 30,
+			features: []string{"UnlimitedSwipes", "ProfileBoost"},
+			check: func(t *testing.T, saved *models.User) {
+				assert.Equal(t, models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true}, saved.PremiumFeatures)
+				require.NotNil(t, saved.PremiumExpiry)
+				assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), *saved.PremiumExpiry, time.Minute)
 			},
-			userID:        1,
-			duration:      30,
-			features:      []string{"UnlimitedSwipes", "IsVerified"},
-			expectedError: "",
 		},
 		{
-			name: "Error - User Not Found",
-			setupMocks: func() {
-				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
-			},
+			name:          "Error - User Not Found",
 			userID:        1,
 			duration:      30,
 			features:      []string{"UnlimitedSwipes"},
 			expectedError: "user not found",
 		},
 		{
-			name: "Error - User Is Inactive",
-			setupMocks: func() {
-				mockRepo.On("GetUserByID", 1).Return(&models.User{
-					ID:         1,
-					IsInactive: true,
-				}, nil)
-			},
+			name:          "Error - User Is Inactive",
+			user:          &models.User{ID: 1, IsInactive: true},
 			userID:        1,
 			duration:      30,
 			features:      []string{"UnlimitedSwipes"},
 			expectedError: "user account is inactive",
 		},
 		{
-			name: "Error - Invalid Feature",
-			setupMocks: func() {
-				mockRepo.On("GetUserByID", 1).Return(&models.User{
-					ID:         1,
-					IsInactive: false,
-				}, nil)
-			},
+			name:          "Error - Invalid Feature",
+			user:          &models.User{ID: 1},
 			userID:        1,
 			duration:      30,
 			features:      []string{"InvalidFeature"},
 			expectedError: "invalid premium feature: InvalidFeature",
 		},
 		{
-			name: "Error - Unlimited Swipes Already Active",
-			setupMocks: func() {
-				mockRepo.On("GetUserByID", 1).Return(&models.User{
-					ID:         1,
-					IsInactive: false,
-					PremiumFeatures: models.PremiumFeatures{
-						UnlimitedSwipes: true,
-						IsVerified:      false,
-					},
-				}, nil)
+			name: "Success - Active Unlimited Swipes Are Renewed",
+			user: &models.User{
+				ID:              1,
+				PremiumExpiry:   utils.TimePtr(time.Now().Add(24 * time.Hour)),
+				PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true},
+			},
+			userID:   1,
+			duration: 30,
+			features: []string{"UnlimitedSwipes"},
+			check: func(t *testing.T, saved *models.User) {
+				assert.Equal(t, models.PremiumFeatures{UnlimitedSwipes: true}, saved.PremiumFeatures)
+				assert.True(t, saved.PremiumExpiry.After(time.Now().Add(30*24*time.Hour)))
+			},
+		},
+		{
+			name: "Success - Expired Unlimited Swipes Can Be Bought Again",
+			user: &models.User{
+				ID:              1,
+				PremiumExpiry:   utils.TimePtr(time.Now().Add(-time.Hour)),
+				PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true},
+			},
+			userID:   1,
+			duration: 30,
+			features: []string{"UnlimitedSwipes"},
+			// Only the purchased feature is active again, the other expired one stays off
+			check: func(t *testing.T, saved *models.User) {
+				assert.Equal(t, models.PremiumFeatures{UnlimitedSwipes: true}, saved.PremiumFeatures)
+				assert.True(t, saved.PremiumExpiry.After(time.Now().Add(29*24*time.Hour)))
+			},
+		},
+		{
+			name:     "Success - Enable Rewind",
+			user:     &models.User{ID: 1},
+			userID:   1,
+			duration: 30,
+			features: []string{"Rewind"},
+			check: func(t *testing.T, saved *models.User) {
+				assert.True(t, saved.PremiumFeatures.Rewind)
+			},
+		},
+		{
+			name:     "Success - Legacy IsVerified Name Enables Profile Boost",
+			user:     &models.User{ID: 1},
+			userID:   1,
+			duration: 30,
+			features: []string{"IsVerified"},
+			check: func(t *testing.T, saved *models.User) {
+				assert.True(t, saved.PremiumFeatures.ProfileBoost)
+				assert.False(t, saved.IsVerified)
 			},
-			userID:        1,
-			duration:      30,
-			features:      []string{"UnlimitedSwipes"},
-			expectedError: "unlimited swipes is already active",
 		},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Reset mock calls
-			mockRepo.ExpectedCalls = nil
-			tc.setupMocks()
-
-			// Call EnablePremiumFeature
-			err := service.EnablePremiumFeature(tc.userID, tc.duration, tc.features)
+	for name, newRepo := range userRepositoryFactories(t) {
+		for _, tc := range testCases {
+			t.Run(name+" - "+tc.name, func(t *testing.T) {
+				repo := newRepo()
+				service := services.NewUserService(repo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+				if tc.user != nil {
+					user := *tc.user
+					seedUsers(t, repo, &user)
+				}
 
-			// Assertions
-			if tc.expectedError == "" {
-				assert.Nil(t, err)
-			} else {
-				assert.NotNil(t, err)
-				assert.Equal(t, tc.expectedError, err.Error())
-			}
+				err := service.EnablePremiumFeature(tc.userID, tc.duration, tc.features)
 
-			mockRepo.AssertExpectations(t)
-		})
+				if tc.expectedError != "" {
+					assert.EqualError(t, err, tc.expectedError)
+					return
+				}
+				require.NoError(t, err)
+				saved, err := repo.GetUserByID(tc.userID)
+				require.NoError(t, err)
+				tc.check(t, saved)
+			})
+		}
 	}
 }
@@ -0,0 +1,135 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurchasePlan(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockPlanRepo := new(userMock.MockPlanRepository)
+	service := services.NewUserService(mockRepo, mockPlanRepo, repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+
+	quarterly := &models.Plan{
+		ID:           "quarterly",
+		DurationDays: 90,
+		Price:        129000,
+		Currency:     "IDR",
+		Features:     []string{"UnlimitedSwipes", "ProfileBoost"},
+	}
+	monthly := &models.Plan{ID: "monthly", DurationDays: 30, Price: 49000, Currency: "IDR", Features: []string{"UnlimitedSwipes"}}
+	inDays := func(days int) *time.Time { return utils.TimePtr(time.Now().Add(time.Duration(days) * 24 * time.Hour)) }
+	around := func(expiry *time.Time, days int) bool {
+		return expiry != nil && expiry.Sub(*inDays(days)).Abs() < time.Minute
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		planID        string
+		expectedError string
+	}{
+		{
+			name: "Success - Plan Resolved Into Expiry And Features",
+			setupMocks: func() {
+				mockPlanRepo.On("GetPlanByID", "quarterly").Return(quarterly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					expectedExpiry := time.Now().Add(90 * 24 * time.Hour)
+					return user.PremiumFeatures.UnlimitedSwipes && user.PremiumFeatures.ProfileBoost &&
+						user.PremiumExpiry.Sub(expectedExpiry).Abs() < time.Minute
+				})).Return(nil)
+			},
+			planID: "quarterly",
+		},
+		{
+			name: "Success - Renewal While Premium Is Active",
+			setupMocks: func() {
+				mockPlanRepo.On("GetPlanByID", "quarterly").Return(quarterly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   utils.TimePtr(time.Now().Add(10 * 24 * time.Hour)),
+					PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, Rewind: true},
+				}, nil)
+
+				// The plan's days are added to the remaining ones and its features to the active ones
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					expectedExpiry := time.Now().Add(100 * 24 * time.Hour)
+					return user.PremiumFeatures == models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true, Rewind: true} &&
+						user.PremiumExpiry.Sub(expectedExpiry).Abs() < time.Minute
+				})).Return(nil)
+			},
+			planID: "quarterly",
+		},
+		{
+			name: "Success - Cheaper Plan Only Extends Its Own Features",
+			setupMocks: func() {
+				mockPlanRepo.On("GetPlanByID", "monthly").Return(monthly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{
+					ID:              1,
+					PremiumExpiry:   inDays(60),
+					PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true},
+					PremiumFeatureExpiry: models.PremiumFeatureExpiry{
+						UnlimitedSwipes: inDays(60),
+						ProfileBoost:    inDays(60),
+					},
+				}, nil)
+
+				// Profile boost was paid for until day 60 and stays so, only unlimited swipes run longer
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.PremiumFeatures == models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true} &&
+						around(user.PremiumFeatureExpiry.UnlimitedSwipes, 90) && around(user.PremiumFeatureExpiry.ProfileBoost, 60) &&
+						user.PremiumFeatureExpiry.Rewind == nil && around(user.PremiumExpiry, 90)
+				})).Return(nil)
+			},
+			planID: "monthly",
+		},
+		{
+			name: "Error - Unknown Plan",
+			setupMocks: func() {
+				mockPlanRepo.On("GetPlanByID", "lifetime").Return(nil, errors.New("plan not found"))
+			},
+			planID:        "lifetime",
+			expectedError: "plan not found",
+		},
+		{
+			name: "Error - Inactive User",
+			setupMocks: func() {
+				mockPlanRepo.On("GetPlanByID", "quarterly").Return(quarterly, nil)
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, IsInactive: true}, nil)
+			},
+			planID:        "quarterly",
+			expectedError: "user account is inactive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockPlanRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			plan, err := service.PurchasePlan(1, tc.planID)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.planID, plan.ID)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockPlanRepo.AssertExpectations(t)
+		})
+	}
+}
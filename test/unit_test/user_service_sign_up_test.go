@@ -1,85 +1,187 @@
 package unit_test
 
 import (
-	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
 	"github.com/GradiyantoS/go-dealls-test-app/services"
-	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/utils"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSignUp(t *testing.T) {
-	mockRepo := new(userMock.MockUserRepository)
-	service := services.NewUserService(mockRepo)
+	adultBirthdate := models.Date{Time: time.Now().AddDate(-18, 0, 0)}
 
 	testCases := []struct {
 		name          string
-		setupMocks    func()
+		seed          func(t *testing.T, repo repositories.UserRepository)
 		input         models.User
 		expectedError string
+		check         func(t *testing.T, saved *models.User)
 	}{
 		{
 			name: "Success",
-			setupMocks: func() {
-				mockRepo.On("GetUserByEmail", "newuser@example.com").Return((*models.User)(nil), errors.New("user not found"))
-				mockRepo.On("GetUserByPhone", "1234567890").Return((*models.User)(nil), errors.New("user not found"))
-				mockRepo.On("GenerateUserID").Return(1)
-				mockRepo.On("SaveUser", mock.AnythingOfType("*models.User")).Return(nil)
+			input: models.User{
+				Email:     "newuser@example.com",
+				Password:  "password123",
+				Phone:     "1234567890",
+				Name:      "New User",
+				Gender:    "male",
+				Birthdate: adultBirthdate,
+			},
+			check: func(t *testing.T, saved *models.User) {
+				assert.Equal(t, "New User", saved.Name)
+				assert.NotEqual(t, "password123", saved.Password)
+				assert.False(t, saved.CreatedAt.IsZero())
+			},
+		},
+		{
+			name: "Success - Premium Fields Are Ignored",
+			input: models.User{
+				Email:           "newuser@example.com",
+				Password:        "password123",
+				Phone:           "1234567890",
+				Name:            "New User",
+				Gender:          "male",
+				Birthdate:       adultBirthdate,
+				PremiumExpiry:   utils.TimePtr(time.Now().AddDate(1, 0, 0)),
+				PremiumFeatures: models.PremiumFeatures{UnlimitedSwipes: true, ProfileBoost: true, Rewind: true},
+			},
+			check: func(t *testing.T, saved *models.User) {
+				assert.Nil(t, saved.PremiumExpiry)
+				assert.Equal(t, models.PremiumFeatures{}, saved.PremiumFeatures)
+			},
+		},
+		{
+			name: "Success - Interests Are Normalized",
+			input: models.User{
+				Email:     "newuser@example.com",
+				Password:  "password123",
+				Phone:     "1234567890",
+				Name:      "New User",
+				Gender:    "male",
+				Birthdate: adultBirthdate,
+				Bio:       "  Hello ",
+				Interests: []string{"Hiking", " music", "hiking"},
+			},
+			check: func(t *testing.T, saved *models.User) {
+				assert.Equal(t, []string{"hiking", "music"}, saved.Interests)
+				assert.Equal(t, "Hello", saved.Bio)
 			},
+		},
+		{
+			name: "Error - Bio Too Long",
+			input: models.User{
+				Email:     "newuser@example.com",
+				Phone:     "1234567890",
+				Birthdate: adultBirthdate,
+				Bio:       strings.Repeat("a", 501),
+			},
+			expectedError: "bio is too long",
+		},
+		{
+			name: "Error - Invalid Interest",
 			input: models.User{
-				Email:    "newuser@example.com",
-				Password: "password123",
-				Phone:    "1234567890",
-				Name:     "New User",
-				Gender:   "male",
+				Email:     "newuser@example.com",
+				Phone:     "1234567890",
+				Birthdate: adultBirthdate,
+				Interests: []string{"hiking", " "},
 			},
-			expectedError: "",
+			expectedError: "invalid interest: ",
+		},
+		{
+			name: "Error - Invalid Preferences",
+			input: models.User{
+				Email:       "newuser@example.com",
+				Phone:       "1234567890",
+				Birthdate:   adultBirthdate,
+				Preferences: models.Preferences{InterestedIn: []string{"female"}, MinAge: 40, MaxAge: 30},
+			},
+			expectedError: "min_age must not be greater than max_age",
 		},
 		{
 			name: "Error - Email Already Exists",
-			setupMocks: func() {
-				mockRepo.On("GetUserByEmail", "existing@example.com").Return(&models.User{Email: "existing@example.com"}, nil)
+			seed: func(t *testing.T, repo repositories.UserRepository) {
+				seedUsers(t, repo, &models.User{ID: repo.GenerateUserID(), Email: "existing@example.com"})
 			},
 			input: models.User{
-				Email: "existing@example.com",
+				Email:     "existing@example.com",
+				Phone:     "1234567890",
+				Birthdate: adultBirthdate,
 			},
 			expectedError: "email already exists",
 		},
 		{
 			name: "Error - Phone Number Already Exists",
-			setupMocks: func() {
-				mockRepo.On("GetUserByEmail", "newuser@example.com").Return((*models.User)(nil), errors.New("user not found"))
-				mockRepo.On("GetUserByPhone", "1234567890").Return(&models.User{Phone: "1234567890"}, nil)
+			seed: func(t *testing.T, repo repositories.UserRepository) {
+				seedUsers(t, repo, &models.User{ID: repo.GenerateUserID(), Phone: "1234567890"})
 			},
 			input: models.User{
-				Email:    "newuser@example.com",
-				Password: "password123",
-				Phone:    "1234567890",
-				Name:     "New User",
-				Gender:   "male",
+				Email:     "newuser@example.com",
+				Password:  "password123",
+				Phone:     "1234567890",
+				Name:      "New User",
+				Gender:    "male",
+				Birthdate: adultBirthdate,
 			},
 			expectedError: "phone number already exists",
 		},
+		{
+			name: "Error - Birthdate Missing",
+			input: models.User{
+				Email: "newuser@example.com",
+				Phone: "1234567890",
+			},
+			expectedError: "birthdate is required",
+		},
+		{
+			name: "Error - Under 18",
+			input: models.User{
+				Email:     "newuser@example.com",
+				Phone:     "1234567890",
+				Birthdate: models.Date{Time: time.Now().AddDate(-18, 0, 1)},
+			},
+			expectedError: "you must be at least 18 years old",
+		},
+		{
+			name: "Error - Birthdate In The Future",
+			input: models.User{
+				Email:     "newuser@example.com",
+				Phone:     "1234567890",
+				Birthdate: models.Date{Time: time.Now().AddDate(0, 0, 2)},
+			},
+			expectedError: "birthdate must not be in the future",
+		},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			mockRepo.ExpectedCalls = nil // Reset mocks
-			tc.setupMocks()
-
-			err := service.SignUp(&tc.input)
+	for name, newRepo := range userRepositoryFactories(t) {
+		for _, tc := range testCases {
+			t.Run(name+" - "+tc.name, func(t *testing.T) {
+				repo := newRepo()
+				service := services.NewUserService(repo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+				if tc.seed != nil {
+					tc.seed(t, repo)
+				}
 
-			if tc.expectedError == "" {
-				assert.Nil(t, err)
-			} else {
-				assert.NotNil(t, err)
-				assert.Equal(t, tc.expectedError, err.Error())
-			}
+				input := tc.input
+				err := service.SignUp(&input)
 
-			mockRepo.AssertExpectations(t)
-		})
+				if tc.expectedError != "" {
+					assert.EqualError(t, err, tc.expectedError)
+					return
+				}
+				require.NoError(t, err)
+				saved, err := repo.GetUserByEmail(tc.input.Email)
+				require.NoError(t, err)
+				assert.Equal(t, input.ID, saved.ID)
+				if tc.check != nil {
+					tc.check(t, saved)
+				}
+			})
+		}
 	}
 }
@@ -0,0 +1,74 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateLocation(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	service := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		location      models.Location
+		expectedError string
+	}{
+		{
+			name: "Success",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.Location != nil && *user.Location == models.Location{Latitude: -6.2, Longitude: 106.8}
+				})).Return(nil)
+			},
+			location: models.Location{Latitude: -6.2, Longitude: 106.8},
+		},
+		{
+			name:          "Error - Latitude Out Of Range",
+			setupMocks:    func() {},
+			location:      models.Location{Latitude: 91, Longitude: 106.8},
+			expectedError: "latitude must be between -90 and 90",
+		},
+		{
+			name:          "Error - Longitude Out Of Range",
+			setupMocks:    func() {},
+			location:      models.Location{Latitude: -6.2, Longitude: -181},
+			expectedError: "longitude must be between -180 and 180",
+		},
+		{
+			name: "Error - User Not Found",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
+			},
+			location:      models.Location{Latitude: -6.2, Longitude: 106.8},
+			expectedError: "user not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			_, err := service.UpdateLocation(1, tc.location)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateProfile(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	service := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+
+	strPtr := func(value string) *string { return &value }
+	existingUser := func() *models.User {
+		return &models.User{ID: 1, Email: "test@example.com", Password: "hashed", Phone: "1234567890", Name: "Old Name", Gender: "male"}
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		update        models.ProfileUpdate
+		expectedError string
+	}{
+		{
+			name: "Success - Partial Update",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(existingUser(), nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.Name == "New Name" && user.Bio == "Hello" && user.Gender == "male" &&
+						user.Phone == "1234567890" && assert.ObjectsAreEqual([]string{"hiking", "jazz"}, user.Interests)
+				})).Return(nil)
+			},
+			update: models.ProfileUpdate{
+				Name:      strPtr(" New Name "),
+				Bio:       strPtr("Hello"),
+				Interests: &[]string{"Hiking", "jazz", "hiking"},
+			},
+		},
+		{
+			name: "Success - Phone Changed",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(existingUser(), nil)
+				mockRepo.On("GetUserByPhone", "0987654321").Return(nil, errors.New("user not found"))
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return user.Phone == "0987654321"
+				})).Return(nil)
+			},
+			update: models.ProfileUpdate{Phone: strPtr("0987654321")},
+		},
+		{
+			name: "Error - Phone Taken By Another User",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(existingUser(), nil)
+				mockRepo.On("GetUserByPhone", "0987654321").Return(&models.User{ID: 2}, nil)
+			},
+			update:        models.ProfileUpdate{Phone: strPtr("0987654321")},
+			expectedError: "phone number already exists",
+		},
+		{
+			name: "Success - Preferences Replaced",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(existingUser(), nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool {
+					return assert.ObjectsAreEqual(models.Preferences{InterestedIn: []string{"male", "female"}, MinAge: 25, MaxAge: 35}, user.Preferences)
+				})).Return(nil)
+			},
+			update: models.ProfileUpdate{Preferences: &models.Preferences{InterestedIn: []string{"male", "female"}, MinAge: 25, MaxAge: 35}},
+		},
+		{
+			name:          "Error - Preferences Without Genders",
+			setupMocks:    func() {},
+			update:        models.ProfileUpdate{Preferences: &models.Preferences{MinAge: 25}},
+			expectedError: "interested_in must contain at least one gender",
+		},
+		{
+			name:          "Error - Inverted Age Range",
+			setupMocks:    func() {},
+			update:        models.ProfileUpdate{Preferences: &models.Preferences{InterestedIn: []string{"female"}, MinAge: 40, MaxAge: 30}},
+			expectedError: "min_age must not be greater than max_age",
+		},
+		{
+			name:          "Error - Empty Name",
+			setupMocks:    func() {},
+			update:        models.ProfileUpdate{Name: strPtr("   ")},
+			expectedError: "name must not be empty",
+		},
+		{
+			name:          "Error - Invalid Gender",
+			setupMocks:    func() {},
+			update:        models.ProfileUpdate{Gender: strPtr("robot")},
+			expectedError: "invalid gender",
+		},
+		{
+			name:          "Error - Invalid Phone",
+			setupMocks:    func() {},
+			update:        models.ProfileUpdate{Phone: strPtr("call me")},
+			expectedError: "invalid phone number",
+		},
+		{
+			name: "Error - User Not Found",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(nil, errors.New("user not found"))
+			},
+			update:        models.ProfileUpdate{Bio: strPtr("Hello")},
+			expectedError: "user not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			user, err := service.UpdateProfile(1, tc.update)
+
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+				assert.Empty(t, user.Password)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
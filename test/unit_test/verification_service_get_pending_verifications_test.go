@@ -0,0 +1,78 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPendingVerifications(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockVerificationRepo := new(userMock.MockVerificationRepository)
+	userService := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+	service := services.NewVerificationService(mockRepo, mockVerificationRepo, new(userMock.MockVerificationChecker), userService)
+
+	pending := []models.VerificationRequest{
+		{ID: 1, UserID: 2, Status: models.VerificationStatusPending},
+		{ID: 2, UserID: 3, Status: models.VerificationStatusPending},
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		expectedUsers []*models.User
+	}{
+		{
+			name: "Success - Requests Carry Their Users",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetPendingVerifications").Return(pending)
+				mockRepo.On("GetUserByID", 2).Return(&models.User{ID: 2, Email: "two@example.com"}, nil)
+				mockRepo.On("GetUserByID", 3).Return(&models.User{ID: 3, Email: "three@example.com"}, nil)
+			},
+			expectedUsers: []*models.User{
+				{ID: 2, Email: "two@example.com"},
+				{ID: 3, Email: "three@example.com"},
+			},
+		},
+		{
+			name: "Success - Missing User Leaves The Request Without One",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetPendingVerifications").Return(pending)
+				mockRepo.On("GetUserByID", 2).Return(&models.User{ID: 2, Email: "two@example.com"}, nil)
+				mockRepo.On("GetUserByID", 3).Return(nil, errors.New("user not found"))
+			},
+			expectedUsers: []*models.User{{ID: 2, Email: "two@example.com"}, nil},
+		},
+		{
+			name: "Success - Empty Queue",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetPendingVerifications").Return([]models.VerificationRequest{})
+			},
+			expectedUsers: []*models.User{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockVerificationRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			result := service.GetPendingVerifications()
+
+			assert.Len(t, result, len(tc.expectedUsers))
+			for i, entry := range result {
+				assert.Equal(t, pending[i].ID, entry.Request.ID)
+				assert.Equal(t, tc.expectedUsers[i], entry.User)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockVerificationRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package unit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReviewVerification(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockVerificationRepo := new(userMock.MockVerificationRepository)
+	userService := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+	service := services.NewVerificationService(mockRepo, mockVerificationRepo, new(userMock.MockVerificationChecker), userService)
+
+	withStatus := func(status string) *models.VerificationRequest {
+		return &models.VerificationRequest{ID: 1, UserID: 2, Status: status}
+	}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		decision      string
+		reason        string
+		expectedError string
+	}{
+		{
+			name: "Success - Approve Sets The Verified Badge",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetVerificationByID", 1).Return(withStatus(models.VerificationStatusPending), nil)
+				mockRepo.On("GetUserByID", 2).Return(&models.User{ID: 2}, nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool { return user.IsVerified })).Return(nil)
+				mockVerificationRepo.On("UpdateVerification", mock.MatchedBy(func(request *models.VerificationRequest) bool {
+					return request.Status == models.VerificationStatusApproved && request.ReviewedBy == models.VerificationReviewerAdmin && request.ReviewedAt != nil
+				})).Return(nil)
+			},
+			decision: "approve",
+		},
+		{
+			name: "Success - Reject Leaves The User Unverified",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetVerificationByID", 1).Return(withStatus(models.VerificationStatusPending), nil)
+				mockVerificationRepo.On("UpdateVerification", mock.MatchedBy(func(request *models.VerificationRequest) bool {
+					return request.Status == models.VerificationStatusRejected && request.Reason == "blurry photo"
+				})).Return(nil)
+			},
+			decision: "reject",
+			reason:   "blurry photo",
+		},
+		{
+			name:          "Error - Rejection Without Reason",
+			setupMocks:    func() {},
+			decision:      "reject",
+			expectedError: "a reason is required to reject a verification request",
+		},
+		{
+			name:          "Error - Unknown Decision",
+			setupMocks:    func() {},
+			decision:      "maybe",
+			expectedError: "decision must be approve or reject",
+		},
+		{
+			name: "Error - Already Reviewed",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetVerificationByID", 1).Return(withStatus(models.VerificationStatusRejected), nil)
+			},
+			decision:      "approve",
+			expectedError: "verification request has already been reviewed",
+		},
+		{
+			name: "Error - Request Not Found",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetVerificationByID", 1).Return(nil, errors.New("verification request not found"))
+			},
+			decision:      "approve",
+			expectedError: "verification request not found",
+		},
+		{
+			name: "Error - Badge Not Saved Keeps The Request Pending",
+			setupMocks: func() {
+				mockVerificationRepo.On("GetVerificationByID", 1).Return(withStatus(models.VerificationStatusPending), nil)
+				mockRepo.On("GetUserByID", 2).Return(nil, errors.New("user not found"))
+			},
+			decision:      "approve",
+			expectedError: "user not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockVerificationRepo.ExpectedCalls = nil
+			tc.setupMocks()
+
+			_, err := service.ReviewVerification(1, tc.decision, tc.reason)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockVerificationRepo.AssertExpectations(t)
+		})
+	}
+}
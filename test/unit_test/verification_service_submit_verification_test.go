@@ -0,0 +1,159 @@
+package unit_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/GradiyantoS/go-dealls-test-app/models"
+	"github.com/GradiyantoS/go-dealls-test-app/repositories"
+	"github.com/GradiyantoS/go-dealls-test-app/services"
+	userMock "github.com/GradiyantoS/go-dealls-test-app/test/mock"
+	"github.com/GradiyantoS/go-dealls-test-app/verification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pngDocument is enough of a PNG file for the content type to be detected
+var pngDocument = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestSubmitVerification(t *testing.T) {
+	mockRepo := new(userMock.MockUserRepository)
+	mockVerificationRepo := new(userMock.MockVerificationRepository)
+	mockChecker := new(userMock.MockVerificationChecker)
+	userService := services.NewUserService(mockRepo, repositories.NewPlanRepository(nil), repositories.NewPremiumEventRepository(), services.NewTokenService(repositories.NewTokenRepository()))
+	service := services.NewVerificationService(mockRepo, mockVerificationRepo, mockChecker, userService)
+
+	withStatus := func(status string) interface{} {
+		return mock.MatchedBy(func(request *models.VerificationRequest) bool { return request.Status == status })
+	}
+	queued := func() {
+		mockVerificationRepo.On("GenerateVerificationID").Return(1)
+		mockVerificationRepo.On("SaveVerification", withStatus(models.VerificationStatusPending)).Return(nil)
+	}
+
+	testCases := []struct {
+		name           string
+		setupMocks     func()
+		documentType   string
+		document       []byte
+		expectedStatus string
+		expectedError  string
+	}{
+		{
+			name: "Success - Queued For Review",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockVerificationRepo.On("GetLatestVerificationForUser", 1).Return(nil, errors.New("verification request not found"))
+				queued()
+				mockChecker.On("Check", mock.Anything).Return(verification.Decision{Status: models.VerificationStatusPending}, nil)
+			},
+			documentType:   models.VerificationDocumentSelfie,
+			document:       pngDocument,
+			expectedStatus: models.VerificationStatusPending,
+		},
+		{
+			name: "Success - Approved By The Checker",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockVerificationRepo.On("GetLatestVerificationForUser", 1).Return(nil, errors.New("verification request not found"))
+				queued()
+				mockChecker.On("Check", mock.Anything).Return(verification.Decision{Status: models.VerificationStatusApproved}, nil)
+				mockRepo.On("UpdateUser", mock.MatchedBy(func(user *models.User) bool { return user.IsVerified })).Return(nil)
+				mockVerificationRepo.On("UpdateVerification", withStatus(models.VerificationStatusApproved)).Return(nil)
+			},
+			documentType:   models.VerificationDocumentID,
+			document:       pngDocument,
+			expectedStatus: models.VerificationStatusApproved,
+		},
+		{
+			name: "Success - Rejected By The Checker",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockVerificationRepo.On("GetLatestVerificationForUser", 1).Return(&models.VerificationRequest{ID: 1, UserID: 1, Status: models.VerificationStatusRejected}, nil)
+				mockVerificationRepo.On("GenerateVerificationID").Return(2)
+				mockVerificationRepo.On("SaveVerification", withStatus(models.VerificationStatusPending)).Return(nil)
+				mockChecker.On("Check", mock.Anything).Return(verification.Decision{Status: models.VerificationStatusRejected, Reason: "face not visible"}, nil)
+				mockVerificationRepo.On("UpdateVerification", withStatus(models.VerificationStatusRejected)).Return(nil)
+			},
+			documentType:   models.VerificationDocumentSelfie,
+			document:       pngDocument,
+			expectedStatus: models.VerificationStatusRejected,
+		},
+		{
+			name: "Success - Checker Failure Leaves The Request Pending",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockVerificationRepo.On("GetLatestVerificationForUser", 1).Return(nil, errors.New("verification request not found"))
+				queued()
+				mockChecker.On("Check", mock.Anything).Return(verification.Decision{}, errors.New("checker unavailable"))
+			},
+			documentType:   models.VerificationDocumentSelfie,
+			document:       pngDocument,
+			expectedStatus: models.VerificationStatusPending,
+		},
+		{
+			name:          "Error - Unknown Document Type",
+			setupMocks:    func() {},
+			documentType:  "passport_photo",
+			document:      pngDocument,
+			expectedError: "document_type must be selfie or id_document",
+		},
+		{
+			name:          "Error - Not An Image Or PDF",
+			setupMocks:    func() {},
+			documentType:  models.VerificationDocumentSelfie,
+			document:      []byte("just some text"),
+			expectedError: "document must be a JPEG, PNG or PDF file",
+		},
+		{
+			name:          "Error - Document Too Large",
+			setupMocks:    func() {},
+			documentType:  models.VerificationDocumentSelfie,
+			document:      append(append([]byte{}, pngDocument...), bytes.Repeat([]byte{0}, services.MaxVerificationDocumentSize)...),
+			expectedError: "document must not be larger than 5 MB",
+		},
+		{
+			name: "Error - Already Verified",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1, IsVerified: true}, nil)
+			},
+			documentType:  models.VerificationDocumentSelfie,
+			document:      pngDocument,
+			expectedError: "user is already verified",
+		},
+		{
+			name: "Error - Request Already Pending",
+			setupMocks: func() {
+				mockRepo.On("GetUserByID", 1).Return(&models.User{ID: 1}, nil)
+				mockVerificationRepo.On("GetLatestVerificationForUser", 1).Return(&models.VerificationRequest{ID: 1, UserID: 1, Status: models.VerificationStatusPending}, nil)
+			},
+			documentType:  models.VerificationDocumentSelfie,
+			document:      pngDocument,
+			expectedError: "a verification request is already pending",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			mockVerificationRepo.ExpectedCalls = nil
+			mockChecker.ExpectedCalls = nil
+			tc.setupMocks()
+
+			request, err := service.SubmitVerification(1, tc.documentType, tc.document)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, request.Status)
+				assert.Equal(t, "image/png", request.ContentType)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockVerificationRepo.AssertExpectations(t)
+			mockChecker.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"os"
 	"time"
@@ -8,6 +10,16 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// AccessTokenTTL is how long an access token stays valid, clients renew it with a refresh token
+const AccessTokenTTL = 15 * time.Minute
+
+// TokenClaims holds the claims of a validated access token
+type TokenClaims struct {
+	UserID    int
+	ID        string // jti, used to revoke the token before it expires
+	ExpiresAt time.Time
+}
+
 // getJWTSecret retrieves the JWT secret from environment variables or defaults
 func getJWTSecret() string {
 	secret := os.Getenv("JWT_SECRET_KEY")
@@ -17,11 +29,16 @@ func getJWTSecret() string {
 	return secret
 }
 
-// GenerateJWT creates a new JWT token for a user
+// GenerateJWT creates a new short-lived JWT token for a user
 var GenerateJWT = func(userID int) (string, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(), // Token expires in 24 hours
+		"jti":     jti,
+		"exp":     time.Now().Add(AccessTokenTTL).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(getJWTSecret())) // Dynamically fetch the secret
@@ -29,6 +46,15 @@ var GenerateJWT = func(userID int) (string, error) {
 
 // ValidateJWT validates and parses a JWT token, returning the user ID if valid
 func ValidateJWT(tokenStr string) (int, error) {
+	claims, err := ParseJWT(tokenStr)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+// ParseJWT validates a JWT token and returns its claims
+func ParseJWT(tokenStr string) (*TokenClaims, error) {
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 		// Ensure the signing method is HMAC
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -38,11 +64,33 @@ func ValidateJWT(tokenStr string) (int, error) {
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return int(claims["user_id"].(float64)), nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, jwt.ErrInvalidKey
+	}
+	result := &TokenClaims{UserID: int(userID)}
+	if jti, ok := claims["jti"].(string); ok {
+		result.ID = jti
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		result.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return result, nil
+}
+
+// GenerateRandomToken returns a hex encoded, cryptographically random string of n bytes
+func GenerateRandomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
 	}
-	return 0, jwt.ErrInvalidKey
+	return hex.EncodeToString(buf), nil
 }
@@ -6,10 +6,24 @@ import (
 )
 
 func DataSuccessResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	response := map[string]interface{}{
+	writeDataResponse(w, statusCode, map[string]interface{}{
 		"data": data,
+	})
+}
+
+// PaginatedSuccessResponse adds the cursor of the next page to the data envelope, it is null on the last page
+func PaginatedSuccessResponse(w http.ResponseWriter, statusCode int, data interface{}, nextCursor string) {
+	response := map[string]interface{}{
+		"data":        data,
+		"next_cursor": nil,
+	}
+	if nextCursor != "" {
+		response["next_cursor"] = nextCursor
 	}
+	writeDataResponse(w, statusCode, response)
+}
 
+func writeDataResponse(w http.ResponseWriter, statusCode int, response map[string]interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
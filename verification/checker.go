@@ -0,0 +1,17 @@
+package verification
+
+import "github.com/GradiyantoS/go-dealls-test-app/models"
+
+// Decision is the outcome of an automated check. Status is one of the verification statuses,
+// pending leaves the request in the queue for an administrator.
+type Decision struct {
+	Status string
+	Reason string
+}
+
+// Checker automatically reviews submitted verification requests, the service only talks to this interface
+type Checker interface {
+	// Name is recorded as the reviewer of the requests the checker decides
+	Name() string
+	Check(request models.VerificationRequest) (Decision, error)
+}
@@ -0,0 +1,19 @@
+package verification
+
+import "github.com/GradiyantoS/go-dealls-test-app/models"
+
+// ManualReview is a Checker that decides nothing, every request waits for an administrator
+type ManualReview struct{}
+
+// NewManualReview creates a checker leaving every request to the administrators
+func NewManualReview() *ManualReview {
+	return &ManualReview{}
+}
+
+func (c *ManualReview) Name() string {
+	return "manual"
+}
+
+func (c *ManualReview) Check(request models.VerificationRequest) (Decision, error) {
+	return Decision{Status: models.VerificationStatusPending}, nil
+}